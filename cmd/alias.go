@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/alias"
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var (
+	aliasCmd = &cobra.Command{
+		Use:   "alias [name] [version]",
+		Short: "Manage named aliases for Go versions",
+		Long: `Give a Go version a name such as prod, ci or legacy.
+
+An alias can be used anywhere a version is accepted: gvm use, install, exec,
+uninstall, new -V and .go-version files. The target may be a full version
+(1.21.5) or a minor version (1.21, the newest installed patch release).
+
+Examples:
+  gvm alias prod 1.21.5
+  gvm alias list
+  gvm use prod
+  gvm alias rm prod`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := alias.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "alias %s -> %s saved\n", args[0], args[1])
+			if pkg.LocalInstalled(args[1]) == nil {
+				prettyout.PrettyWarm(os.Stdout, "%s is not installed, install it with \"gvm install %s\"\n", args[1], args[0])
+			}
+			return nil
+		},
+	}
+	aliasListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "List aliases and their target versions",
+		Aliases: []string{"l", "ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases, err := alias.List()
+			if err != nil {
+				return err
+			}
+			if len(aliases) == 0 {
+				cmd.Println("no alias configured, add one with \"gvm alias <name> <version>\"")
+				return nil
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tVERSION\tINSTALLED")
+			for _, a := range aliases {
+				installed := "no"
+				if v := pkg.LocalInstalled(a.Version); v != nil {
+					installed = "go" + v.String()
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", a.Name, a.Version, installed)
+			}
+			return w.Flush()
+		},
+	}
+	aliasRemoveCmd = &cobra.Command{
+		Use:     "remove [name]",
+		Short:   "Remove an alias",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := alias.Remove(args[0]); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "alias %s removed\n", args[0])
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+}
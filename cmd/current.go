@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var currentCmd = &cobra.Command{
+	Use:     "current",
+	Aliases: []string{"which"},
+	Short:   "Show the active Go version and why it was chosen",
+	Long: `Show the Go version gvm resolves for the current directory, its GOROOT,
+where the decision came from (GVM_GO_VERSION, a project file such as
+.go-version or go.mod, or the global version set by "gvm use") and whether
+the first "go" on PATH actually runs that GOROOT.
+
+Use --json in scripts and shell prompts.
+
+Examples:
+  gvm current
+  gvm which --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		status, err := pkg.Current(wd)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(status)
+		}
+
+		out := cmd.OutOrStdout()
+		switch {
+		case status.Installed:
+			fmt.Fprintf(out, "go%s\n", status.Version)
+		case status.Request != "":
+			fmt.Fprintf(out, "go%s (not installed)\n", status.Request)
+		default:
+			fmt.Fprintln(out, "no Go version selected, run \"gvm use <version>\"")
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+		fmt.Fprintf(w, "  GOROOT:\t%s\n", status.GoRoot)
+		fmt.Fprintf(w, "  Source:\t%s\n", status.Source)
+		switch {
+		case status.PathGo == "":
+			fmt.Fprintf(w, "  PATH:\tno go command found on PATH\n")
+		case status.ViaShim:
+			fmt.Fprintf(w, "  PATH:\t%s (gvm shim)\n", status.PathGo)
+		case status.PathMatches:
+			fmt.Fprintf(w, "  PATH:\t%s\n", status.PathGo)
+		default:
+			fmt.Fprintf(w, "  PATH:\t%s does not belong to this GOROOT, see \"gvm init\"\n", status.PathGo)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(currentCmd)
+	currentCmd.Flags().Bool("json", false, "Print the status as JSON")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [version] -- [command] [args...]",
+	Short: "Run a command with a specific Go version without switching",
+	Long: `Run a single command with PATH, GOROOT and GOTOOLCHAIN=local set for the
+given Go version. The global version set by "gvm use" is left untouched.
+
+When the version is omitted ("gvm exec -- go test ./..."), the version pinned
+by the project (.go-version, go.work, go.mod) or the global version is used.
+
+Signals are forwarded to the command and its exit code is passed through.
+
+Examples:
+  gvm exec 1.20 -- go test ./...
+  gvm exec -- go version
+  gvm exec --install 1.21.5 -- go build -o app .`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionName, command, err := shell.SplitExec(args, cmd.ArgsLenAtDash())
+		if err != nil {
+			return err
+		}
+		if versionName == "" {
+			if versionName, err = execProjectVersion(); err != nil {
+				return err
+			}
+		}
+
+		v := pkg.LocalInstalled(versionName)
+		if v == nil {
+			if !autoInstall(cmd) {
+				return fmt.Errorf("version %q not found, use \"gvm install %s\" first or pass --install", versionName, versionName)
+			}
+			if v, err = pkg.InstallOnly(versionName); err != nil {
+				return err
+			}
+		}
+
+		code, err := pkg.Exec(v, command[0], command[1:])
+		if err != nil {
+			return err
+		}
+		os.Exit(code)
+		return nil
+	},
+}
+
+// execProjectVersion 返回省略版本时使用的版本：项目锁定的版本，没有时为全局版本
+func execProjectVersion() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	res, err := pkg.Resolve(wd)
+	if err != nil {
+		return "", err
+	}
+	if res.Source != pkg.SourceGlobal {
+		return res.Request, nil
+	}
+	if !res.Installed() {
+		return "", fmt.Errorf("no Go version selected, run \"gvm use <version>\" first or pass a version")
+	}
+	return res.Version.String(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().BoolP("install", "i", false, "Install the version first if it is missing (default from auto_install config)")
+}
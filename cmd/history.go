@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/history"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent version switches and installs",
+	Long: `List recent "gvm use" switches, installs and uninstalls, newest first.
+
+The history is kept in ~/.gvm/history.jsonl and also drives "gvm use -".
+
+Examples:
+  gvm history
+  gvm history -n 5
+  gvm history --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		entries, err := history.List()
+		if err != nil {
+			return err
+		}
+		slices.Reverse(entries)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[:limit]
+		}
+
+		if asJSON {
+			if entries == nil {
+				entries = []history.Entry{}
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+		if len(entries) == 0 {
+			cmd.Println("no history yet")
+			return nil
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tACTION\tVERSION")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\tgo%s\n", e.Time.Local().Format(time.DateTime), e.Action, e.Version)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntP("limit", "n", 20, "Number of entries to show, 0 for all")
+	historyCmd.Flags().Bool("json", false, "Print the history as JSON")
+}
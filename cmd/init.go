@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var (
+	initCmd = &cobra.Command{
+		Use:       "init [bash|zsh|fish]",
+		Short:     "Print shell integration code",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{string(shell.Bash), string(shell.Zsh), string(shell.Fish)},
+		Long: `Print shell code that puts the global Go version on PATH, sets GOROOT and
+installs a hook that runs whenever the current directory changes.
+
+The hook applies the version pinned by .go-version, .gvmrc, go.work or go.mod
+to the current shell session only; the global version set by "gvm use" is not
+touched. Outside of a pinned project the global version is restored.
+
+Add one of these lines to your shell profile:
+  eval "$(gvm init bash)"     # ~/.bashrc
+  eval "$(gvm init zsh)"      # ~/.zshrc
+  gvm init fish | source      # ~/.config/fish/config.fish`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sh, err := shell.Parse(args[0])
+			if err != nil {
+				return err
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				exe = consts.NAME
+			}
+			fmt.Fprint(cmd.OutOrStdout(), shell.InitScript(sh, exe, consts.GO_ROOT))
+			return nil
+		},
+	}
+	hookCmd = &cobra.Command{
+		Use:    "hook [bash|zsh|fish]",
+		Short:  "Print environment changes for the current directory",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sh, err := shell.Parse(args[0])
+			if err != nil {
+				return err
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			res, err := pkg.Resolve(wd)
+			if err != nil {
+				prettyout.PrettyWarm(os.Stderr, "gvm: %s\n", err.Error())
+				res = pkg.Resolution{Source: pkg.SourceGlobal}
+			} else if !res.Installed() && res.Source != pkg.SourceGlobal {
+				prettyout.PrettyWarm(os.Stderr, "gvm: %s\n", res.NotInstalledError())
+				res = pkg.Resolution{Source: pkg.SourceGlobal}
+			}
+
+			goRoot := res.GoRoot()
+			gopath := pkg.PkgsetPath(goRoot)
+			previous := os.Getenv(shell.ActiveRootEnv)
+			previousGopath := os.Getenv(shell.ActiveGopathEnv)
+			entries := shell.RewritePath(os.Getenv("PATH"), goRoot, previous, consts.GO_ROOT, previousGopath, gopath)
+			entries = shell.WithGopath(entries, gopath)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, sh.Export("GOROOT", goRoot))
+			fmt.Fprintln(out, sh.Export(shell.ActiveRootEnv, goRoot))
+			fmt.Fprintln(out, sh.ExportPath(entries))
+			// 离开 pkgset 时恢复用户原来的 GOPATH 与 GOBIN，原来没有设置时恢复 go 的默认值
+			for _, line := range shell.PkgsetScript(sh, gopath, os.LookupEnv) {
+				fmt.Fprintln(out, line)
+			}
+			writeProfileEnv(out, sh, goRoot)
+			return nil
+		},
+	}
+)
+
+// writeProfileEnv 导出配置 envs 中该版本的环境变量，并还原上一个版本设置而当前版本没有的变量
+func writeProfileEnv(out io.Writer, sh shell.Shell, goRoot string) {
+	env, err := pkg.ProfileEnv(goRoot)
+	if err != nil {
+		prettyout.PrettyWarm(os.Stderr, "gvm: %s\n", err.Error())
+	}
+	for _, line := range shell.ProfileScript(sh, env, os.LookupEnv) {
+		fmt.Fprintln(out, line)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(hookCmd)
+}
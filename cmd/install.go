@@ -7,23 +7,39 @@ import (
 	"errors"
 	"github.com/spf13/cobra"
 	"github.com/the-yex/gvm/internal/core"
+	"github.com/the-yex/gvm/internal/project"
+	"github.com/the-yex/gvm/pkg"
 	"os"
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install",
-	Short: "A brief description of your command",
-	Args:  cobra.MinimumNArgs(1),
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:   "install [version]",
+	Short: "Install a Go version",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Install a Go version from the configured mirror and switch to it.
+
+Without a version, gvm installs the toolchain the current project asks for:
+.go-version/.gvmrc, then the toolchain or go directive of go.work, then go.mod.
+A language version such as "go 1.21" installs the latest 1.21.x patch release.
+
+Examples:
+  gvm install 1.22.3
+  gvm install 1.21     # choose a 1.21.x release interactively
+  gvm install          # install the project's pinned toolchain`,
 	Run: func(cmd *cobra.Command, args []string) {
-		installVersion := args[0]
-		err := core.InstallVersion(installVersion)
+		var err error
+		if len(args) == 0 {
+			var pin project.Pin
+			if pin, err = findProjectPin(); err != nil {
+				cmd.PrintErrln(err.Error())
+				return
+			}
+			cmd.Printf("Found go%s\n", pin)
+			err = pkg.InstallLatestMatch(pin.Version)
+		} else {
+			err = core.InstallVersion(args[0])
+		}
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			cmd.PrintErrln(err.Error())
 		}
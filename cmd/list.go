@@ -4,12 +4,18 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/listing"
 	list2 "github.com/the-yex/gvm/internal/tui/list"
+	"github.com/the-yex/gvm/internal/version"
 	"github.com/the-yex/gvm/pkg"
+	"os"
 	"slices"
 	"time"
 )
@@ -25,7 +31,18 @@ Example:
   gvm list -r
     Show all available Go versions remotely.
   gvm list -r --refresh
-    Refresh the remote cache before listing.`,
+    Refresh the remote cache before listing.
+  gvm list -r -o json
+    Print remote versions with install state and the archive for this platform as JSON.
+  gvm list -r -t stable -o wide
+    Print the plain table with the archive file name, URL and checksum.
+  gvm list -r -c ">=1.21 <1.23" --latest-per-minor
+    Show the newest patch of each minor version between 1.21 and 1.23.
+  gvm list -r --not-installed --os windows --arch arm64
+    Show versions not installed here that ship an archive for windows/arm64.
+
+When stdout is not a terminal the interactive list is skipped and the plain
+table is printed, unless --output says otherwise.`,
 	Aliases: []string{"l", "ls"},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		remote, _ := cmd.Flags().GetBool("remote")
@@ -38,16 +55,29 @@ Example:
 			}
 		}
 
+		filter, err := listFilter(cmd, remote)
+		if err != nil {
+			return err
+		}
+
+		format, err := listFormat(cmd)
+		if err != nil {
+			return err
+		}
+
 		timeout, _ := cmd.Flags().GetDuration("timeout")
 		mirrorFlag, _ := cmd.Flags().GetString("mirror")
 		refresh, _ := cmd.Flags().GetBool("refresh")
-		opts := pkg.ListOption{Timeout: timeout, Mirror: mirrorFlag, Refresh: refresh}
+		opts := pkg.ListOption{Timeout: timeout, Mirror: mirrorFlag, Refresh: refresh, Quiet: format != listing.TUI}
 
 		versions, err := pkg.NewVManager(remote, pkg.WithLocal()).List(vk, opts)
 		if err != nil {
 			return err
 		}
-		versions = slices.Compact(versions)
+		versions = filter.Apply(slices.Compact(versions))
+		if format != listing.TUI {
+			return listing.Write(cmd.OutOrStdout(), format, versions, filter.OS, filter.Arch)
+		}
 		items := make([]list.Item, len(versions))
 		for index, v := range versions {
 			items[index] = v
@@ -65,11 +95,46 @@ Example:
 			Timeout: timeout,
 			Remote:  remote,
 		}
+		if remote {
+			cacheInfo := pkg.RemoteCacheInfoSnapshot()
+			footer.ServedBy = cacheInfo.ServedBy
+			footer.Cached = cacheInfo.Used
+		}
 		list2.NewListProgram(items, title, footer).Run()
 		return nil
 	},
 }
 
+// listFilter 根据命令行选项构造筛选条件，--os 与 --arch 需要远程版本的安装包信息，只能与 --remote 同时使用
+func listFilter(cmd *cobra.Command, remote bool) (filter version.Filter, err error) {
+	if c, _ := cmd.Flags().GetString("constraint"); c != "" {
+		if filter.Constraint, err = version.NewConstraint(c); err != nil {
+			return filter, fmt.Errorf("invalid constraint %q: %w", c, err)
+		}
+	}
+	filter.Installed, _ = cmd.Flags().GetBool("installed")
+	filter.NotInstalled, _ = cmd.Flags().GetBool("not-installed")
+	filter.OS, _ = cmd.Flags().GetString("os")
+	filter.Arch, _ = cmd.Flags().GetString("arch")
+	if (filter.OS != "" || filter.Arch != "") && !remote {
+		return filter, errors.New("--os and --arch require --remote")
+	}
+	filter.LatestPerMinor, _ = cmd.Flags().GetBool("latest-per-minor")
+	return filter, nil
+}
+
+// listFormat 返回输出格式：未指定 --output 时，标准输出是终端则使用交互列表，否则输出纯文本表格
+func listFormat(cmd *cobra.Command) (listing.Format, error) {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "" {
+		return listing.ParseFormat(output)
+	}
+	if isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		return listing.TUI, nil
+	}
+	return listing.Plain, nil
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolP("remote", "r", false, "List remote Go versions")
@@ -77,4 +142,12 @@ func init() {
 	listCmd.Flags().DurationP("timeout", "T", 5*time.Second, "HTTP timeout for fetching remote versions")
 	listCmd.Flags().StringP("mirror", "m", "", "Override mirror URL (temporary, does not save to config)")
 	listCmd.Flags().Bool("refresh", false, "Force refresh remote version cache")
+	listCmd.Flags().StringP("output", "o", "", "Output format: tui | json | yaml | plain | wide (default tui on a terminal, plain otherwise)")
+	listCmd.Flags().StringP("constraint", "c", "", `Only list versions matching the constraint, e.g. ">=1.21 <1.23"`)
+	listCmd.Flags().Bool("installed", false, "Only list installed versions")
+	listCmd.Flags().Bool("not-installed", false, "Only list versions that are not installed")
+	listCmd.Flags().String("os", "", "Only list versions with an archive for this OS (requires --remote)")
+	listCmd.Flags().String("arch", "", "Only list versions with an archive for this architecture (requires --remote)")
+	listCmd.Flags().Bool("latest-per-minor", false, "Only list the newest version of each minor release")
+	listCmd.MarkFlagsMutuallyExclusive("installed", "not-installed")
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/matrix"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix [--versions list] -- [command] [args...]",
+	Short: "Run a command under several installed Go versions",
+	Long: `Run the same command once per installed Go version and print a summary.
+
+Each entry of --versions may be an exact version (1.21.5), a minor version
+(1.21, resolves to the newest installed patch), "latest" (newest installed
+stable release) or a constraint (">=1.21") matching every installed version.
+A comma inside a constraint (">=1.21,<1.23") joins its conditions.
+Without --versions all installed versions are used.
+
+With --parallel every version gets its own GOCACHE so builds do not contend
+for the same cache. The command exits non-zero if any version failed.
+
+Examples:
+  gvm matrix --versions "1.21,1.22,latest" -- go test ./...
+  gvm matrix -p --versions ">=1.21,<1.23" -o junit -- go test ./... > report.xml`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dash := cmd.ArgsLenAtDash(); dash > 0 {
+			return fmt.Errorf("unexpected arguments before \"--\": %q", args[:dash])
+		}
+		values, _ := cmd.Flags().GetStringArray("versions")
+		parallel, _ := cmd.Flags().GetBool("parallel")
+		output, _ := cmd.Flags().GetString("output")
+		format, err := matrix.ParseFormat(output)
+		if err != nil {
+			return err
+		}
+
+		versions, err := pkg.MatrixVersions(matrix.SplitVersions(values))
+		if err != nil {
+			return err
+		}
+		targets := make([]matrix.Target, 0, len(versions))
+		for _, v := range versions {
+			env, err := pkg.Environ(v.LocalDir(), os.Environ())
+			if err != nil {
+				return err
+			}
+			if parallel {
+				env = shell.WithEnv(env, "GOCACHE", filepath.Join(consts.CACHE_DIR, "gocache", v.DirName))
+			}
+			targets = append(targets, matrix.Target{Version: v.String(), GoRoot: v.LocalDir(), Env: env})
+		}
+
+		// 表格输出时日志写到 stdout；JSON 与 JUnit 报告写到 stdout，日志改写到 stderr
+		stream := cmd.OutOrStdout()
+		if format != matrix.Table {
+			stream = cmd.ErrOrStderr()
+		}
+		results := matrix.Run(targets, args[0], args[1:], matrix.Options{Parallel: parallel, Stream: stream})
+		if format == matrix.Table {
+			fmt.Fprintln(stream)
+		}
+		if err = matrix.Write(cmd.OutOrStdout(), format, strings.Join(args, " "), results); err != nil {
+			return err
+		}
+		if matrix.Failed(results) > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(matrixCmd)
+	matrixCmd.Flags().StringArray("versions", nil, "Comma separated versions, \"latest\" or constraints, may be repeated (default: all installed)")
+	matrixCmd.Flags().BoolP("parallel", "p", false, "Run all versions in parallel with separate GOCACHE dirs")
+	matrixCmd.Flags().StringP("output", "o", string(matrix.Table), "Output format: table | json | junit")
+}
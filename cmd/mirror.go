@@ -0,0 +1,208 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/internal/registry"
+)
+
+var (
+	mirrorCmd = &cobra.Command{
+		Use:   "mirror",
+		Short: "Manage Go download mirrors",
+		Long: `Manage the mirrors gvm can download Go from.
+
+Each mirror has a name, a base URL and a parser type that tells gvm how to read
+its index page: official | json | fancyindex | autoindex | directory | local.
+A "local" mirror is a directory (or file:// URL) holding Go archives plus their
+.sha256 files, for machines without internet access.
+
+Examples:
+  gvm mirror list
+  gvm mirror add corp https://artifactory.example.com/golang/ --parser directory
+  gvm mirror add offline /srv/golang --parser local
+  gvm mirror remove corp
+  gvm mirror bench             # find the fastest mirror
+  gvm config set mirror corp   # use a mirror by name`,
+	}
+	mirrorListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "List configured mirrors",
+		Aliases: []string{"l", "ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mirrors, err := registry.ConfiguredMirrors()
+			if err != nil {
+				return err
+			}
+			current := viper.GetString(consts.CONFIG_MIRROR)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\tNAME\tURL\tPARSER")
+			for _, m := range mirrors {
+				mark := ""
+				if strings.EqualFold(m.Name, current) || strings.TrimSuffix(m.URL, "/") == strings.TrimSuffix(current, "/") {
+					mark = "*"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, m.Name, m.URL, m.Parser)
+			}
+			return w.Flush()
+		},
+	}
+	mirrorAddCmd = &cobra.Command{
+		Use:   "add [name] [url]",
+		Short: "Add or update a mirror",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, _ := cmd.Flags().GetString("parser")
+			parser, err := registry.ParseParser(p)
+			if err != nil {
+				return err
+			}
+			m := registry.Mirror{Name: args[0], URL: args[1], Parser: parser}
+			if parser == registry.ParserLocal && !strings.HasPrefix(m.URL, "file://") {
+				if m.URL, err = filepath.Abs(m.URL); err != nil {
+					return err
+				}
+			}
+			if err = registry.AddMirror(m); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "mirror %s saved\n", m)
+			return nil
+		},
+	}
+	mirrorRemoveCmd = &cobra.Command{
+		Use:     "remove [name]",
+		Short:   "Remove a mirror",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if m, err := registry.LookupMirror(viper.GetString(consts.CONFIG_MIRROR)); err == nil && strings.EqualFold(m.Name, name) {
+				prettyout.PrettyWarm(os.Stdout, "%s is the current mirror, set another one with \"gvm config set mirror <name>\"\n", name)
+			}
+			if err := registry.RemoveMirror(name); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "mirror %s removed\n", name)
+			return nil
+		},
+	}
+	mirrorBenchCmd = &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark mirrors and pick the fastest one",
+		Long: `Probe every configured mirror concurrently, measuring index fetch latency and
+download throughput (a ranged GET on the latest Go archive for this platform).
+
+Mirrors whose version list lags behind the others are flagged, and the fastest
+up-to-date mirror can be saved as the default mirror.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			sample, _ := cmd.Flags().GetInt64("sample")
+			yes, _ := cmd.Flags().GetBool("yes")
+			mirrors, err := registry.ConfiguredMirrors()
+			if err != nil {
+				return err
+			}
+			if sample <= 0 {
+				return fmt.Errorf("sample size must be positive")
+			}
+			if len(mirrors) == 0 {
+				return fmt.Errorf("no mirror configured, add one with \"gvm mirror add\"")
+			}
+
+			cmd.Printf("Benchmarking %d mirrors...\n\n", len(mirrors))
+			results := registry.Bench(mirrors, timeout, sample)
+			printBenchResults(cmd, results)
+
+			winner := results[0]
+			if winner.Err != nil {
+				return fmt.Errorf("no mirror is reachable")
+			}
+			current, _ := registry.LookupMirror(viper.GetString(consts.CONFIG_MIRROR))
+			if current.URL == winner.Mirror.URL {
+				prettyout.PrettyInfo(os.Stdout, "\n%s is already the default mirror\n", winner.Mirror.Name)
+				return nil
+			}
+			if !yes && !confirm(cmd, fmt.Sprintf("\nUse %s as the default mirror? [y/N] ", winner.Mirror.Name)) {
+				return nil
+			}
+			viper.Set(consts.CONFIG_MIRROR, winner.Mirror.Name)
+			if err = viper.WriteConfig(); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "default mirror set to %s\n", winner.Mirror)
+			return nil
+		},
+	}
+)
+
+func printBenchResults(cmd *cobra.Command, results []registry.BenchResult) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tNAME\tLATENCY\tSPEED\tVERSIONS\tLATEST\tNOTE")
+	for i, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%d\t%s\t-\t-\t-\t-\t%s\n", i+1, r.Mirror.Name, firstLine(r.Err.Error()))
+			continue
+		}
+		speed, latest, note := "-", "-", ""
+		if r.Local {
+			speed = "local"
+		} else if r.Throughput > 0 {
+			speed = formatSpeed(r.Throughput)
+		}
+		if r.Latest != nil {
+			latest = r.Latest.String()
+		}
+		if r.Behind {
+			note = "behind"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			i+1, r.Mirror.Name, r.Latency.Round(time.Millisecond), speed, r.Versions, latest, note)
+	}
+	w.Flush()
+}
+
+func formatSpeed(speed float64) string {
+	if speed >= 1024*1024 {
+		return fmt.Sprintf("%.2f MB/s", speed/1024/1024)
+	}
+	return fmt.Sprintf("%.2f KB/s", speed/1024)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// confirm 在终端中询问用户，仅当输入 y/yes 时返回 true
+func confirm(cmd *cobra.Command, prompt string) bool {
+	cmd.Print(prompt)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+	mirrorCmd.AddCommand(mirrorListCmd)
+	mirrorCmd.AddCommand(mirrorAddCmd)
+	mirrorCmd.AddCommand(mirrorRemoveCmd)
+	mirrorCmd.AddCommand(mirrorBenchCmd)
+	mirrorBenchCmd.Flags().DurationP("timeout", "T", 10*time.Second, "HTTP timeout for each probe")
+	mirrorBenchCmd.Flags().Int64("sample", 1<<20, "Bytes to download when measuring throughput")
+	mirrorBenchCmd.Flags().BoolP("yes", "y", false, "Save the fastest mirror without asking")
+	mirrorAddCmd.Flags().StringP("parser", "p", string(registry.ParserFancyIndex), "Index parser: official | json | fancyindex | autoindex | directory | local")
+}
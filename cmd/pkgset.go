@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/pkgset"
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/internal/version"
+	"github.com/the-yex/gvm/pkg"
+)
+
+var (
+	pkgsetCmd = &cobra.Command{
+		Use:   "pkgset",
+		Short: "Manage per-version GOPATH/GOBIN sets",
+		Long: `Give each Go version its own GOPATH and GOBIN so tools installed with
+"go install" under one version (gopls, golangci-lint...) are not shared with
+other versions.
+
+Pkgsets live in ~/.gvm/pkgsets/go<version>/<name>. The selected pkgset is
+applied by "gvm exec", "gvm matrix", the go shims and the "gvm init" hook.
+~/.gvm/gopath always points to the pkgset of the global version, so a static
+profile can use GOPATH=~/.gvm/gopath and PATH=~/.gvm/gopath/bin:$PATH.
+
+Commands act on the version in effect in the current directory unless
+--version is given. Set "pkgset_isolation: true" to give every version a
+"default" pkgset without selecting one.
+
+Examples:
+  gvm pkgset create tools
+  gvm pkgset use tools
+  gvm pkgset list --version 1.20
+  gvm pkgset use --off
+  gvm pkgset delete tools`,
+	}
+	pkgsetCreateCmd = &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a pkgset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := pkgsetVersion(cmd)
+			if err != nil {
+				return err
+			}
+			p := pkgset.Pkgset{Version: v.DirName, Name: args[0]}
+			if err = pkgset.Create(p); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "pkgset %s created at %s\n", p, p.Dir())
+			return nil
+		},
+	}
+	pkgsetUseCmd = &cobra.Command{
+		Use:   "use [name]",
+		Short: "Select the pkgset for a version",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			off, _ := cmd.Flags().GetBool("off")
+			if off == (len(args) == 1) {
+				return fmt.Errorf("specify a pkgset name or --off")
+			}
+			v, err := pkgsetVersion(cmd)
+			if err != nil {
+				return err
+			}
+			if off {
+				err = pkgset.Off(v.DirName)
+			} else {
+				err = pkgset.Use(pkgset.Pkgset{Version: v.DirName, Name: args[0]})
+			}
+			if err != nil {
+				return err
+			}
+			if err = pkg.RelinkPkgset(); err != nil {
+				return err
+			}
+			if p, ok := pkgset.Active(v.DirName); ok {
+				prettyout.PrettyInfo(os.Stdout, "go%s now uses pkgset %s\n", v, p.Name)
+			} else {
+				prettyout.PrettyInfo(os.Stdout, "go%s now uses the default GOPATH\n", v)
+			}
+			return nil
+		},
+	}
+	pkgsetListCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "List pkgsets of a version",
+		Aliases: []string{"l", "ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := pkgsetVersion(cmd)
+			if err != nil {
+				return err
+			}
+			names, err := pkgset.List(v.DirName)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				cmd.Printf("go%s has no pkgset, create one with \"gvm pkgset create <name>\"\n", v)
+				return nil
+			}
+			active, _ := pkgset.Active(v.DirName)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\tNAME\tGOPATH")
+			for _, name := range names {
+				mark := ""
+				if name == active.Name {
+					mark = "*"
+				}
+				p := pkgset.Pkgset{Version: v.DirName, Name: name}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", mark, name, p.Dir())
+			}
+			return w.Flush()
+		},
+	}
+	pkgsetDeleteCmd = &cobra.Command{
+		Use:     "delete [name]",
+		Short:   "Delete a pkgset and the tools installed in it",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := pkgsetVersion(cmd)
+			if err != nil {
+				return err
+			}
+			p := pkgset.Pkgset{Version: v.DirName, Name: args[0]}
+			if err = pkgset.Delete(p); err != nil {
+				return err
+			}
+			if err = pkg.RelinkPkgset(); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "pkgset %s deleted\n", p)
+			return nil
+		},
+	}
+)
+
+func pkgsetVersion(cmd *cobra.Command) (*version.Version, error) {
+	versionName, _ := cmd.Flags().GetString("version")
+	return pkg.PkgsetVersion(versionName)
+}
+
+func init() {
+	rootCmd.AddCommand(pkgsetCmd)
+	pkgsetCmd.AddCommand(pkgsetCreateCmd)
+	pkgsetCmd.AddCommand(pkgsetUseCmd)
+	pkgsetCmd.AddCommand(pkgsetListCmd)
+	pkgsetCmd.AddCommand(pkgsetDeleteCmd)
+	pkgsetCmd.PersistentFlags().StringP("version", "V", "", "Go version to manage (default: the version in effect here)")
+	pkgsetUseCmd.Flags().Bool("off", false, "Stop using a pkgset and fall back to the default GOPATH")
+}
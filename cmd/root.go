@@ -4,8 +4,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/lock"
+	"github.com/the-yex/gvm/internal/registry"
+	"github.com/the-yex/gvm/internal/shim"
+	"github.com/the-yex/gvm/pkg"
 	"os"
 	"strings"
 
@@ -35,6 +40,13 @@ Perfect for developers who work on projects that require different Go versions.`
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// 以 ~/.gvm/shims 中的 go/gofmt 身份被调用时，直接执行解析出的版本
+	if name, ok := shim.Name(os.Args[0]); ok {
+		initializeConfig()
+		err := pkg.ExecShim(name, os.Args[1:])
+		fmt.Fprintf(os.Stderr, "gvm: %s\n", err)
+		os.Exit(1)
+	}
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -66,10 +78,18 @@ func initializeConfig() {
 	viper.AddConfigPath(".")
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
+	viper.SetDefault(consts.CONFIG_MIRROR_FALLBACKS, []string{})
+	viper.SetDefault(consts.CONFIG_PROXY, "")
+	viper.SetDefault(consts.CONFIG_CA_FILE, "")
+	viper.SetDefault(consts.CONFIG_USER_AGENT, "")
+	viper.SetDefault(consts.CONFIG_LOCK_TIMEOUT, lock.DefaultTimeout.String())
+	viper.SetDefault(consts.CONFIG_AUTO_INSTALL, false)
+	viper.SetDefault(consts.CONFIG_PKGSET_ISOLATION, false)
 
 	if err := viper.ReadInConfig(); err != nil {
 		// basic configs
 		viper.Set(consts.CONFIG_MIRROR, consts.DEFAULT_MIRROR)
+		viper.Set(consts.CONFIG_MIRRORS, registry.MirrorsConfig(registry.DefaultMirrors()))
 		viper.Set(consts.CONFIG_GOROOT, []string{home + consts.DEFAULT_GOROOT})
 		viper.SafeWriteConfig()
 	}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/internal/shim"
+)
+
+var shimsCmd = &cobra.Command{
+	Use:   "shims",
+	Short: "Create go and gofmt shims that pick the Go version per invocation",
+	Long: `Create ~/.gvm/shims/go and ~/.gvm/shims/gofmt. Each shim is the gvm binary
+under another name; on every invocation it resolves the Go version and runs
+that version's bin/go (or bin/gofmt):
+
+  1. the GVM_GO_VERSION environment variable
+  2. .go-version, .gvmrc, go.work or go.mod found from the current directory
+  3. the global version set by "gvm use"
+
+Put the shims directory first on PATH so IDEs, make and scripts pick the
+project's version without any shell hook.
+
+Examples:
+  gvm shims
+  GVM_GO_VERSION=1.21 go test ./...
+  gvm shims --remove`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remove, _ := cmd.Flags().GetBool("remove")
+		if remove {
+			if err := shim.Remove(consts.SHIMS_DIR); err != nil {
+				return err
+			}
+			prettyout.PrettyInfo(os.Stdout, "shims removed from %s\n", consts.SHIMS_DIR)
+			return nil
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		created, err := shim.Install(consts.SHIMS_DIR, exe)
+		if err != nil {
+			return err
+		}
+		for _, file := range created {
+			prettyout.PrettyInfo(os.Stdout, "created %s\n", file)
+		}
+		fmt.Printf("\nAdd the shims directory to the front of PATH:\n  export PATH=\"%s:$PATH\"\n", consts.SHIMS_DIR)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shimsCmd)
+	shimsCmd.Flags().Bool("remove", false, "Remove the shims")
+}
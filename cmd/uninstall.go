@@ -4,8 +4,12 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/the-yex/gvm/internal/core"
+	"github.com/the-yex/gvm/internal/prettyout"
 	"github.com/the-yex/gvm/pkg"
 )
 
@@ -29,6 +33,9 @@ from your installation path.`,
 			cmd.Printf("Version %q is not installed. Install it with \"gvm install %s\" first.\n", version, version)
 			return
 		}
+		if names := pkg.AliasesOf(v); len(names) > 0 {
+			prettyout.PrettyWarm(os.Stdout, "warning: alias %s currently resolves to go%s\n", strings.Join(names, ", "), v)
+		}
 		err := core.UninstallVersion(v.LocalDir())
 		if err != nil {
 			cmd.PrintErrln(err.Error())
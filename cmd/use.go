@@ -4,12 +4,12 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"errors"
-	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/project"
 	"github.com/the-yex/gvm/pkg"
 	"os"
-	"regexp"
 )
 
 // useCmd represents the use command
@@ -19,18 +19,43 @@ var useCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	Long: `Switch the current Go environment to the specified version.
 
+Without a version, gvm walks up from the current directory looking for a
+.go-version or .gvmrc file, then go.work, then go.mod, and uses the first
+version it finds. In go.work and go.mod the toolchain directive wins over the
+go directive, and a language version such as "go 1.21" matches the newest
+installed 1.21.x release.
+
 Examples:
-  gvm use go1.21    # activate Go 1.21
-  gvm use           # use version from go.mod (if available)`,
+  gvm use go1.21          # activate Go 1.21
+  gvm use                 # use the project's pinned version
+  gvm use --local 1.22    # pin this directory to Go 1.22 and activate it
+  gvm use --install       # install the project's version if missing, then use it
+  gvm use -               # switch back to the previous version, like "cd -"
+
+Set "auto_install: true" in the config to make --install the default.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		local, _ := cmd.Flags().GetBool("local")
+		previous, _ := cmd.Flags().GetBool("previous")
 		version := ""
-		if len(args) == 0 {
-			modVersion, err := getModVersion()
+		if previous || (len(args) == 1 && args[0] == "-") {
+			prev, err := pkg.PreviousVersion()
+			if err != nil {
+				cmd.Println(err.Error())
+				return
+			}
+			version = prev
+		} else if len(args) == 0 {
+			if local {
+				cmd.Println("please specify the version to write to " + project.GoVersionFile)
+				return
+			}
+			pin, err := findProjectPin()
 			if err != nil {
-				fmt.Println(err.Error())
+				cmd.Println(err.Error())
 				return
 			}
-			version = modVersion
+			cmd.Printf("Found go%s\n", pin)
+			version = pin.Version
 		} else {
 			version = args[0]
 		}
@@ -42,43 +67,70 @@ Examples:
 
 		localVersion := pkg.LocalInstalled(version)
 		if localVersion == nil {
-			cmd.Printf("Version %q not found. use  \"gvm install %s\" first\n", version, version)
-			return
+			if !autoInstall(cmd) {
+				cmd.Printf("Version %q not found. use  \"gvm install %s\" first or pass --install\n", version, version)
+				return
+			}
+			var err error
+			if localVersion, err = pkg.InstallOnly(version); err != nil {
+				cmd.Println(err.Error())
+				return
+			}
 		}
 
 		if err := pkg.SwitchVersion(localVersion.LocalDir()); err != nil {
 			cmd.Println(err.Error())
+			return
+		}
+		printProfileEnv(cmd, localVersion.LocalDir())
+		if local {
+			file, err := project.WriteVersionFile(".", version)
+			if err != nil {
+				cmd.Println(err.Error())
+				return
+			}
+			cmd.Printf("Pinned %s to %s\n", file, version)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(useCmd)
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// useCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// useCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	useCmd.Flags().BoolP("local", "l", false, "Write the version to .go-version in the current directory")
+	useCmd.Flags().Bool("previous", false, "Switch back to the previously used version (same as \"gvm use -\")")
+	useCmd.Flags().BoolP("install", "i", false, "Install the version first if it is missing (default from auto_install config)")
 }
 
-var goModReg = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+(?:\.\d+)?(?:beta\d+|rc\d+)?)\s*(?:$|//.*)`)
+// autoInstall 报告版本缺失时是否自动安装：命令行的 --install 优先，未指定时读取 auto_install 配置
+func autoInstall(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("install") {
+		install, _ := cmd.Flags().GetBool("install")
+		return install
+	}
+	return viper.GetBool(consts.CONFIG_AUTO_INSTALL)
+}
 
-func getModVersion() (string, error) {
-	// Uses go.mod if available and version is omitted
-	goModData, err := os.ReadFile("go.mod")
+// printProfileEnv 输出配置 envs 中该版本的环境变量，它们由 gvm init 的 hook 与 gvm exec 应用
+func printProfileEnv(cmd *cobra.Command, goRoot string) {
+	env, err := pkg.ProfileEnv(goRoot)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return "", errors.New("no go.mod file found")
-		}
-		return "", err
+		cmd.Println(err.Error())
+		return
+	}
+	if len(env) == 0 {
+		return
+	}
+	cmd.Println("Environment from config (applied by the gvm init hook and gvm exec):")
+	for _, kv := range env {
+		cmd.Println("  " + kv)
 	}
-	match := goModReg.FindStringSubmatch(string(goModData))
-	if len(match) > 1 {
-		return match[1], nil
+}
+
+// findProjectPin 从当前目录向上查找项目锁定的版本
+func findProjectPin() (project.Pin, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return project.Pin{}, err
 	}
-	return "", errors.New("no version found in go.mod")
+	return project.Find(wd)
 }
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"github.com/the-yex/gvm/internal/utils"
-	"os"
 )
 
 /*
@@ -13,7 +12,7 @@ import (
 * @Package:
  */
 func main() {
-	size, err := utils.DownloadFile("https://golang.google.cn/dl/go1.24.6.darwin-arm64.tar.gz", "./go1.24.6.darwin-arm64.tar.gz", os.O_RDWR|os.O_CREATE, 0600)
+	size, err := utils.DownloadFile("https://golang.google.cn/dl/go1.24.6.darwin-arm64.tar.gz", "./go1.24.6.darwin-arm64.tar.gz", 0600)
 	if err != nil {
 		fmt.Println(err.Error())
 		return
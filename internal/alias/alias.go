@@ -0,0 +1,115 @@
+package alias
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Alias 是版本的别名，如 prod -> 1.21.5
+type Alias struct {
+	Name    string `mapstructure:"name"`
+	Version string `mapstructure:"version"`
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// reserved 是在版本参数中有特殊含义、不能作为别名的名称，
+// 以及 gvm alias 的子命令，否则 "gvm alias list 1.21" 无法设置名为 list 的别名
+var reserved = []string{version.Latest, "system", "list", "ls", "l", "remove", "rm"}
+
+// List 返回配置文件 aliases 中的别名
+func List() ([]Alias, error) {
+	var aliases []Alias
+	if err := viper.UnmarshalKey(consts.CONFIG_ALIASES, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", consts.CONFIG_ALIASES, err)
+	}
+	return aliases, nil
+}
+
+// Lookup 返回别名指向的版本
+func Lookup(name string) (string, bool) {
+	aliases, err := List()
+	if err != nil {
+		return "", false
+	}
+	for _, a := range aliases {
+		if a.Name == name {
+			return a.Version, true
+		}
+	}
+	return "", false
+}
+
+// Resolve 将别名展开为版本，不是别名时原样返回
+func Resolve(versionName string) string {
+	if v, ok := Lookup(strings.TrimSpace(versionName)); ok {
+		return v
+	}
+	return versionName
+}
+
+// Set 添加或更新别名并写回配置文件
+func Set(name, versionName string) error {
+	if err := validate(name); err != nil {
+		return err
+	}
+	versionName = strings.TrimSpace(versionName)
+	if _, ok := Lookup(versionName); ok {
+		return fmt.Errorf("alias %q cannot point to another alias %q", name, versionName)
+	}
+	if _, err := version.NewGoVersion(strings.TrimPrefix(versionName, "go")); err != nil {
+		return fmt.Errorf("invalid version %q for alias %q", versionName, name)
+	}
+	aliases, err := List()
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(aliases, func(a Alias) bool { return a.Name == name }); i >= 0 {
+		aliases[i].Version = versionName
+	} else {
+		aliases = append(aliases, Alias{Name: name, Version: versionName})
+	}
+	return save(aliases)
+}
+
+// Remove 删除别名并写回配置文件
+func Remove(name string) error {
+	aliases, err := List()
+	if err != nil {
+		return err
+	}
+	match := func(a Alias) bool { return a.Name == name }
+	if !slices.ContainsFunc(aliases, match) {
+		return fmt.Errorf("alias %q not found", name)
+	}
+	return save(slices.DeleteFunc(aliases, match))
+}
+
+// validate 检查别名是否合法：不能与版本号或保留字冲突
+func validate(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid alias %q: must start with a letter and contain only letters, digits, '.', '_' or '-'", name)
+	}
+	if slices.Contains(reserved, strings.ToLower(name)) {
+		return fmt.Errorf("invalid alias %q: reserved name", name)
+	}
+	if _, err := version.NewGoVersion(strings.TrimPrefix(name, "go")); err == nil {
+		return fmt.Errorf("invalid alias %q: looks like a version", name)
+	}
+	return nil
+}
+
+func save(aliases []Alias) error {
+	values := make([]map[string]string, 0, len(aliases))
+	for _, a := range aliases {
+		values = append(values, map[string]string{"name": a.Name, "version": a.Version})
+	}
+	viper.Set(consts.CONFIG_ALIASES, values)
+	return viper.WriteConfig()
+}
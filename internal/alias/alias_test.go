@@ -0,0 +1,78 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func TestSetResolveRemove(t *testing.T) {
+	file := testutil.ConfigFile(t, "")
+
+	if err := Set("prod", "1.21.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("legacy", "go1.19"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("prod", "1.22.3"); err != nil {
+		t.Fatal(err)
+	}
+	testutil.ReloadConfig(t, file)
+
+	aliases, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Alias{{Name: "prod", Version: "1.22.3"}, {Name: "legacy", Version: "go1.19"}}
+	if len(aliases) != len(want) {
+		t.Fatalf("List = %+v, want %+v", aliases, want)
+	}
+	for i := range want {
+		if aliases[i] != want[i] {
+			t.Errorf("alias %d = %+v, want %+v", i, aliases[i], want[i])
+		}
+	}
+
+	for in, out := range map[string]string{"prod": "1.22.3", "legacy": "go1.19", "1.20": "1.20", "ci": "ci"} {
+		if got := Resolve(in); got != out {
+			t.Errorf("Resolve(%q) = %q, want %q", in, got, out)
+		}
+	}
+
+	if err = Remove("prod"); err != nil {
+		t.Fatal(err)
+	}
+	testutil.ReloadConfig(t, file)
+	if _, ok := Lookup("prod"); ok {
+		t.Error("prod should be removed")
+	}
+	if err = Remove("prod"); err == nil {
+		t.Error("expected error removing a missing alias")
+	}
+}
+
+func TestSet_Invalid(t *testing.T) {
+	testutil.ConfigFile(t, "")
+	if err := Set("legacy", "1.19"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ name, version string }{
+		{"1.21", "1.21.5"},   // 与版本号冲突
+		{"go1.21", "1.21.5"}, // 与版本号冲突
+		{"latest", "1.21.5"}, // 保留字
+		{"System", "1.21.5"}, // 保留字，不区分大小写
+		{"list", "1.21.5"},   // gvm alias 的子命令
+		{"ls", "1.21.5"},
+		{"l", "1.21.5"},
+		{"remove", "1.21.5"},
+		{"rm", "1.21.5"},
+		{"my alias", "1.21.5"}, // 非法字符
+		{"prod", "not-a-version"},
+		{"prod", "legacy"}, // 不能指向其它别名
+	} {
+		if err := Set(c.name, c.version); err == nil {
+			t.Errorf("Set(%q, %q) should fail", c.name, c.version)
+		}
+	}
+}
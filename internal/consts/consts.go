@@ -26,6 +26,9 @@ var (
 	GO_ROOT     string
 	VERSION_DIR string
 	CACHE_DIR   string
+	SHIMS_DIR   string
+	PKGSET_DIR  string
+	GOPATH_LINK string
 )
 
 func init() {
@@ -34,7 +37,12 @@ func init() {
 	GO_ROOT = filepath.Join(GVM_HOME, "go")
 	VERSION_DIR = filepath.Join(GVM_HOME, "sdk")
 	CACHE_DIR = filepath.Join(GVM_HOME, "cache")
-	for _, dir := range []string{GVM_HOME, GO_ROOT, VERSION_DIR, CACHE_DIR} {
+	SHIMS_DIR = filepath.Join(GVM_HOME, "shims")
+	PKGSET_DIR = filepath.Join(GVM_HOME, "pkgsets")
+	// GOPATH_LINK 是指向全局版本当前 pkgset 的软链接，由 pkgset.Link 维护
+	GOPATH_LINK = filepath.Join(GVM_HOME, "gopath")
+	// GO_ROOT 是指向当前版本的软链接，由 pkg.SwitchVersion 创建
+	for _, dir := range []string{GVM_HOME, VERSION_DIR, CACHE_DIR} {
 		if err := os.MkdirAll(dir, 0755); err != nil && !os.IsExist(err) {
 			panic(fmt.Errorf("创建目录 %s 失败: %w", dir, err))
 		}
@@ -43,8 +51,22 @@ func init() {
 
 const (
 	// config keys
-	CONFIG_MIRROR = "mirror"
-	CONFIG_GOROOT = "goroots"
+	CONFIG_MIRROR           = "mirror"
+	CONFIG_MIRRORS          = "mirrors"
+	CONFIG_MIRROR_FALLBACKS = "mirror_fallbacks"
+	CONFIG_GOROOT           = "goroots"
+	CONFIG_PROXY            = "proxy"
+	CONFIG_CA_FILE          = "ca_file"
+	CONFIG_AUTH             = "auth"
+	CONFIG_USER_AGENT       = "user_agent"
+	CONFIG_LOCK_TIMEOUT     = "lock_timeout"
+	CONFIG_ALIASES          = "aliases"
+	CONFIG_AUTO_INSTALL     = "auto_install"
+	CONFIG_PKGSET_ISOLATION = "pkgset_isolation"
+	CONFIG_ENVS             = "envs"
+
+	// ENV_GO_VERSION 指定单次调用使用的版本，优先于项目版本文件与全局版本
+	ENV_GO_VERSION = "GVM_GO_VERSION"
 
 	EMPTY_INFO     = "<set-correct-info>"
 	DEFAULT_MIRROR = "https://golang.google.cn/dl/"
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/the-yex/gvm/internal/consts"
@@ -20,15 +21,39 @@ var (
 	UninstallVersion   func(version string) error
 	InstallVersion     func(version string) error
 	MultiWriterInstall func(version any, writer io.Writer, fn func(int642 int64)) error
+	// UseVersion 切换全局版本并更新 pkgset、记录历史，与 gvm use 相同
+	UseVersion func(versionDir string) error
 )
 
-func SwitchVersion(versionDir string) error {
-	os.Remove(consts.GO_ROOT)
-	_, err := os.Stat(versionDir)
-	if err != nil {
+// LinkGoRoot 将 GO_ROOT 软链接到 versionDir，调用方需持有全局锁。它只替换软链接，
+// 切换版本请使用 pkg.SwitchVersion 或 UseVersion，它们同时更新 pkgset 并记录历史。
+// 先在 GO_ROOT 旁创建指向 versionDir 的临时链接，再 rename 覆盖 GO_ROOT，
+// 切换过程中 GO_ROOT 始终指向某个完整的版本；失败时恢复原来的链接。
+func LinkGoRoot(versionDir string) error {
+	if _, err := os.Stat(versionDir); err != nil {
 		return err
 	}
-	if err = utils.Symlink(versionDir, consts.GO_ROOT); err != nil {
+	previous, _ := os.Readlink(consts.GO_ROOT)
+	tmp := fmt.Sprintf("%s.switch-%d", consts.GO_ROOT, os.Getpid())
+	os.Remove(tmp)
+	if err := utils.Symlink(versionDir, tmp); err != nil {
+		return err
+	}
+	err := os.Rename(tmp, consts.GO_ROOT)
+	if err == nil {
+		return nil
+	}
+	// Windows 无法 rename 覆盖目录联接，GO_ROOT 也可能是旧版本留下的空目录：
+	// 此时退化为删除后再 rename。非空的真实目录会删除失败，不会误删用户文件。
+	if rmErr := os.Remove(consts.GO_ROOT); rmErr != nil && !os.IsNotExist(rmErr) {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", consts.GO_ROOT, err)
+	}
+	if err = os.Rename(tmp, consts.GO_ROOT); err != nil {
+		os.Remove(tmp)
+		if previous != "" {
+			utils.Symlink(previous, consts.GO_ROOT)
+		}
 		return err
 	}
 	return nil
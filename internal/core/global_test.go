@@ -0,0 +1,85 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func mkVersionDirs(t *testing.T, home string, names ...string) []string {
+	t.Helper()
+	var dirs []string
+	for _, name := range names {
+		dir := filepath.Join(home, "sdk", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func assertLinked(t *testing.T, want string) {
+	t.Helper()
+	got, err := os.Readlink(consts.GO_ROOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("GO_ROOT -> %s, want %s", got, want)
+	}
+}
+
+func TestLinkGoRoot(t *testing.T) {
+	home := testutil.GvmHome(t)
+	dirs := mkVersionDirs(t, home, "go1.21.0", "go1.22.0")
+
+	for _, dir := range append(dirs, dirs[0]) {
+		if err := LinkGoRoot(dir); err != nil {
+			t.Fatal(err)
+		}
+		assertLinked(t, dir)
+	}
+
+	// 目标不存在时保留原链接
+	if err := LinkGoRoot(filepath.Join(home, "sdk", "go1.23.0")); err == nil {
+		t.Fatal("expected error for missing version")
+	}
+	assertLinked(t, dirs[0])
+
+	entries, _ := os.ReadDir(home)
+	for _, entry := range entries {
+		if entry.Name() != "go" && entry.Name() != "sdk" {
+			t.Errorf("unexpected leftover %s", entry.Name())
+		}
+	}
+}
+
+func TestLinkGoRoot_ReplacesEmptyDir(t *testing.T) {
+	home := testutil.GvmHome(t)
+	dirs := mkVersionDirs(t, home, "go1.22.0")
+	if err := os.Mkdir(consts.GO_ROOT, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkGoRoot(dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	assertLinked(t, dirs[0])
+}
+
+func TestLinkGoRoot_KeepsNonEmptyDir(t *testing.T) {
+	home := testutil.GvmHome(t)
+	dirs := mkVersionDirs(t, home, "go1.22.0")
+	if err := os.MkdirAll(filepath.Join(consts.GO_ROOT, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkGoRoot(dirs[0]); err == nil {
+		t.Fatal("expected error when GO_ROOT is a real directory")
+	}
+	if _, err := os.Stat(filepath.Join(consts.GO_ROOT, "bin")); err != nil {
+		t.Error("existing GO_ROOT contents must be kept")
+	}
+}
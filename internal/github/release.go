@@ -26,6 +26,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/mholt/archiver/v3"
 	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/transport"
 	"io"
 	"net/http"
 	"os"
@@ -56,7 +57,7 @@ func (r *Release) FindAsset() (*Asset, error) {
 // Asset contains downloadable resource files.
 type Asset struct {
 	Name               string `json:"name"`
-	tempDir            string `json:"temp_dir"`
+	tempDir            string
 	ContentType        string `json:"content_type"`
 	BrowserDownloadURL string `json:"browser_download_url"`
 }
@@ -84,12 +85,12 @@ func (a *Asset) DownloadWithProgress(onProgress ProgressFunc) (size int64, err e
 	if source := os.Getenv("GVM_SOURCE"); source != "" {
 		url = strings.Replace(url, "gitlab", source, 1)
 	}
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := transport.NewRequest(http.MethodGet, url)
 	if err != nil {
 		return 0, err
 	}
 	req.Header.Set("User-Agent", "gvm")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := transport.Do(req, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -196,13 +197,14 @@ func NewReleaseUpdater() *ReleaseUpdater {
 func (up ReleaseUpdater) CheckForUpdates() (rel *Release, yes bool, err error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", consts.AUTHOR, consts.NAME)
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := transport.NewRequest(http.MethodGet, url)
 	if err != nil {
 		return nil, false, err
 	}
+	req.Header.Set("User-Agent", "gvm")
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := transport.Do(req, 30*time.Second)
 	if err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/the-yex/gvm/internal/consts"
+)
+
+const (
+	// FileName GVM_HOME 下的历史记录文件名，每行一条 JSON 记录
+	FileName = "history.jsonl"
+	// MaxEntries 保留的最大记录数，超过后丢弃最旧的记录
+	MaxEntries = 200
+)
+
+// Action 是被记录的操作
+type Action string
+
+const (
+	Use       Action = "use"
+	Install   Action = "install"
+	Uninstall Action = "uninstall"
+)
+
+// Entry 是一条历史记录
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Action  Action    `json:"action"`
+	Version string    `json:"version"`
+}
+
+// Path 返回历史记录文件路径
+func Path() string {
+	return filepath.Join(consts.GVM_HOME, FileName)
+}
+
+// Record 追加一条记录。先写临时文件再重命名，并发写入时最多丢失一条记录，不会损坏文件。
+func Record(action Action, version string) error {
+	entries, err := List()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, Entry{Time: time.Now(), Action: action, Version: version})
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	tmp, err := os.CreateTemp(consts.GVM_HOME, FileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err = enc.Encode(entry); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), Path())
+}
+
+// List 按时间顺序返回所有记录，无法解析的行会被跳过
+func List() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Version != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Previous 返回最近一次切换到的、与 current 不同的版本，用于 gvm use -
+func Previous(current string) (string, error) {
+	entries, err := List()
+	if err != nil {
+		return "", err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Action == Use && entries[i].Version != current {
+			return entries[i].Version, nil
+		}
+	}
+	return "", fmt.Errorf("no previous version in history")
+}
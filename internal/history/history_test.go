@@ -0,0 +1,82 @@
+package history
+
+import (
+	"os"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func TestRecordPrevious(t *testing.T) {
+	testutil.GvmHome(t)
+
+	if _, err := Previous("1.22.0"); err == nil {
+		t.Error("expected error with empty history")
+	}
+	for _, e := range []struct {
+		action  Action
+		version string
+	}{
+		{Install, "1.21.0"},
+		{Use, "1.21.0"},
+		{Install, "1.22.0"},
+		{Use, "1.22.0"},
+		{Uninstall, "1.20.0"},
+	} {
+		if err := Record(e.action, e.version); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 || entries[4].Action != Uninstall || entries[0].Time.IsZero() {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	// 安装与卸载不影响 gvm use -，与当前版本相同的记录被跳过
+	if prev, err := Previous("1.22.0"); err != nil || prev != "1.21.0" {
+		t.Errorf("Previous = %q, %v, want 1.21.0", prev, err)
+	}
+	if err = Record(Use, "1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if prev, err := Previous("1.21.0"); err != nil || prev != "1.22.0" {
+		t.Errorf("Previous after toggle = %q, %v, want 1.22.0", prev, err)
+	}
+}
+
+func TestRecord_Trim(t *testing.T) {
+	testutil.GvmHome(t)
+	for i := 0; i < MaxEntries+5; i++ {
+		if err := Record(Use, "1.21.0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("got %d entries, want %d", len(entries), MaxEntries)
+	}
+}
+
+func TestList_SkipsCorruptLines(t *testing.T) {
+	testutil.GvmHome(t)
+	data := `{"time":"2025-01-02T03:04:05Z","action":"use","version":"1.21.0"}
+not json
+{"time":"2025-01-02T03:04:06Z","action":"use","version":"1.22.0"}
+`
+	if err := os.WriteFile(Path(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Version != "1.22.0" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
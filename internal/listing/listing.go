@@ -0,0 +1,136 @@
+package listing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"text/tabwriter"
+
+	"github.com/the-yex/gvm/internal/version"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format 是 gvm list 的输出格式
+type Format string
+
+const (
+	TUI   Format = "tui"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	Plain Format = "plain"
+	// Wide 在 Plain 的基础上输出安装包的文件名、URL 与校验和
+	Wide Format = "wide"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case TUI, JSON, YAML, Plain, Wide:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("invalid output format %q, must be tui | json | yaml | plain | wide", s)
+	}
+}
+
+// Item 是一个版本的结构化输出
+type Item struct {
+	Version   string    `json:"version" yaml:"version"`
+	Installed bool      `json:"installed" yaml:"installed"`
+	Current   bool      `json:"current" yaml:"current"`
+	Path      string    `json:"path,omitempty" yaml:"path,omitempty"`
+	Artifact  *Artifact `json:"artifact,omitempty" yaml:"artifact,omitempty"`
+}
+
+// Artifact 是该版本适用于所选平台的安装包
+type Artifact struct {
+	FileName  string `json:"filename" yaml:"filename"`
+	URL       string `json:"url" yaml:"url"`
+	OS        string `json:"os" yaml:"os"`
+	Arch      string `json:"arch" yaml:"arch"`
+	Size      string `json:"size,omitempty" yaml:"size,omitempty"`
+	Checksum  string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+}
+
+// Items 将版本转换为结构化输出，安装包取 goos/goarch 平台（为空的一项取当前平台），本地版本没有安装包信息
+func Items(versions []*version.Version, goos, goarch string) []Item {
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	items := make([]Item, 0, len(versions))
+	for _, v := range versions {
+		item := Item{Version: v.String(), Installed: v.Installed, Current: v.CurrentUsed}
+		if v.Installed {
+			item.Path = v.LocalDir()
+		}
+		if a, err := v.ArtifactFor(goos, goarch); err == nil {
+			item.Artifact = &Artifact{
+				FileName:  a.FileName,
+				URL:       a.URL,
+				OS:        string(a.OS),
+				Arch:      string(a.Arch),
+				Size:      a.Size,
+				Checksum:  a.Checksum,
+				Algorithm: a.Algorithm,
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Write 按 format 输出版本列表，TUI 由调用方处理
+func Write(w io.Writer, format Format, versions []*version.Version, goos, goarch string) error {
+	items := Items(versions, goos, goarch)
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(items); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return writePlain(w, items, format == Wide)
+	}
+}
+
+// writePlain 每行一个版本，当前版本以 * 标记，便于 grep/awk 处理；
+// wide 为 true 时追加安装包的 FILENAME、URL 与 CHECKSUM 列，没有安装包时为 -
+func writePlain(w io.Writer, items []Item, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "\tVERSION\tINSTALLED\tPATH"
+	if wide {
+		header += "\tFILENAME\tURL\tCHECKSUM"
+	}
+	fmt.Fprintln(tw, header)
+	for _, item := range items {
+		mark, installed, path := "", "no", "-"
+		if item.Current {
+			mark = "*"
+		}
+		if item.Installed {
+			installed, path = "yes", item.Path
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s", mark, item.Version, installed, path)
+		if wide {
+			filename, url, checksum := "-", "-", "-"
+			if a := item.Artifact; a != nil {
+				filename, url = a.FileName, a.URL
+				if a.Checksum != "" {
+					checksum = a.Checksum
+				}
+			}
+			fmt.Fprintf(tw, "\t%s\t%s\t%s", filename, url, checksum)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,116 @@
+package listing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/version"
+	"go.yaml.in/yaml/v3"
+)
+
+func testVersions(t *testing.T) []*version.Version {
+	t.Helper()
+	file := fmt.Sprintf("go1.22.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	remote, err := version.NewGoVersion("go1.22.0", version.WithArtifacts([]version.ArtifactInfo{
+		{FileName: "go1.22.0.src.tar.gz", Kind: version.Kind("Source")},
+		{FileName: file, URL: "https://go.dev/dl/" + file, Kind: version.ArchiveKind, OS: version.OS(runtime.GOOS), Arch: version.ARCH(runtime.GOARCH), Checksum: "abc", Algorithm: "SHA256"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed, err := version.NewVersion("1.21.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed.Installed, installed.CurrentUsed = true, true
+	installed.Path, installed.DirName = "/sdk", "go1.21.5"
+	return []*version.Version{installed, remote}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JSON, testVersions(t), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	var items []Item
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items", len(items))
+	}
+	if got := items[0]; !got.Installed || !got.Current || got.Path == "" || got.Artifact != nil {
+		t.Errorf("installed item = %+v", got)
+	}
+	if got := items[1]; got.Installed || got.Artifact == nil || got.Artifact.Checksum != "abc" || !strings.HasSuffix(got.Artifact.URL, ".tar.gz") {
+		t.Errorf("remote item = %+v", got)
+	}
+}
+
+func TestWrite_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, YAML, testVersions(t), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	var items []Item
+	if err := yaml.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].Version != "1.22.0" || items[1].Artifact == nil {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestWrite_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Plain, testVersions(t), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "*") || !strings.Contains(lines[2], "1.22.0") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestWrite_Wide(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Wide, testVersions(t), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[0]); !slices.Equal(fields, []string{"VERSION", "INSTALLED", "PATH", "FILENAME", "URL", "CHECKSUM"}) {
+		t.Errorf("header = %q", fields)
+	}
+	// 本地版本没有安装包信息
+	if fields := strings.Fields(lines[1]); len(fields) != 7 || !slices.Equal(fields[4:], []string{"-", "-", "-"}) {
+		t.Errorf("installed row = %q", fields)
+	}
+	file := fmt.Sprintf("go1.22.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	if fields := strings.Fields(lines[2]); !slices.Equal(fields, []string{"1.22.0", "no", "-", file, "https://go.dev/dl/" + file, "abc"}) {
+		t.Errorf("remote row = %q", fields)
+	}
+}
+
+func TestWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JSON, nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty list = %q, want []", buf.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
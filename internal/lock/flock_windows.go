@@ -0,0 +1,29 @@
+//go:build windows
+
+package lock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset 锁定文件末尾之外的一个字节：LockFileEx 是强制锁，
+// 锁定 PID 所在的区域会让等待的进程无法读取持有者的 PID
+const lockOffset = 1 << 30
+
+// tryLock 以非阻塞方式对 f 加排他锁，被其它句柄持有时返回 false
+func tryLock(f *os.File) (bool, error) {
+	ol := &windows.Overlapped{Offset: lockOffset}
+	err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlock(f *os.File) error {
+	ol := &windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,138 @@
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/prettyout"
+)
+
+const (
+	// FileName GVM_HOME 下的锁文件名
+	FileName = "gvm.lock"
+	// DefaultTimeout 未配置 lock_timeout 时等待锁的时长，足够另一个进程完成一次下载安装
+	DefaultTimeout = 5 * time.Minute
+
+	pollInterval = 100 * time.Millisecond
+)
+
+// HeldError 表示锁在等待超时后仍被其它进程持有
+type HeldError struct {
+	Path string
+	PID  int // 读取不到持有者时为 0
+}
+
+func (e *HeldError) Error() string {
+	if e.PID <= 0 {
+		return fmt.Sprintf("another gvm process is holding %s, retry later", e.Path)
+	}
+	return fmt.Sprintf("another gvm process (pid %d) is holding %s, retry later", e.PID, e.Path)
+}
+
+// Lock 是基于 flock（Windows 上为 LockFileEx）的跨进程互斥锁，锁随文件句柄释放，
+// 持有者进程退出后由系统自动释放，不会留下残留锁。
+// 锁不可重入：同一进程内的两次 Acquire 也会互斥，已持有锁的调用方应调用不加锁的内部函数。
+type Lock struct {
+	f *os.File
+}
+
+// OnWait 在锁被其它进程持有、开始等待时调用一次，pid 读取不到时为 0。
+// 默认输出到标准错误，交互界面替换为发送消息，避免输出覆盖界面
+var OnWait = func(pid int) {
+	if pid > 0 {
+		prettyout.PrettyWarm(os.Stderr, "waiting for another gvm process (pid %d) to finish...\n", pid)
+	} else {
+		prettyout.PrettyWarm(os.Stderr, "waiting for another gvm process to finish...\n")
+	}
+}
+
+// Path 返回全局锁文件路径
+func Path() string {
+	return filepath.Join(consts.GVM_HOME, FileName)
+}
+
+// Timeout 返回 lock_timeout 配置（或 GVM_LOCK_TIMEOUT 环境变量）指定的等待时长
+func Timeout() time.Duration {
+	if timeout := viper.GetDuration(consts.CONFIG_LOCK_TIMEOUT); timeout > 0 {
+		return timeout
+	}
+	return DefaultTimeout
+}
+
+// Do 持有全局锁执行 fn，用于安装、卸载、切换版本等修改 GVM_HOME 的操作
+func Do(fn func() error) error {
+	l, err := Acquire(Path(), Timeout())
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+	return fn()
+}
+
+// Acquire 获取 path 处的锁，被其它进程持有时最多等待 timeout
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock %s: %w", path, err)
+	}
+	deadline := time.Now().Add(timeout)
+	notified := false
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if ok {
+			break
+		}
+		pid := owner(path)
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, &HeldError{Path: path, PID: pid}
+		}
+		if !notified {
+			OnWait(pid)
+			notified = true
+		}
+		time.Sleep(pollInterval)
+	}
+	// PID 只用于提示等待中的进程，写入失败不影响加锁
+	if err = f.Truncate(0); err == nil {
+		fmt.Fprintf(f, "%d\n", os.Getpid())
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release 释放锁。锁文件保留在原处：删除后等待者可能锁住已被删除的文件，与新创建的锁文件并存
+func (l *Lock) Release() error {
+	if l.f == nil {
+		return nil
+	}
+	l.f.Truncate(0)
+	err := unlock(l.f)
+	if closeErr := l.f.Close(); err == nil {
+		err = closeErr
+	}
+	l.f = nil
+	return err
+}
+
+// owner 读取锁文件中持有者的 PID，读取不到时返回 0
+func owner(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	return pid
+}
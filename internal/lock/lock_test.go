@@ -0,0 +1,149 @@
+package lock
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// envHoldLock 设置时测试进程作为子进程持有该路径的锁，直到标准输入关闭
+const envHoldLock = "GVM_TEST_HOLD_LOCK"
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(envHoldLock); path != "" {
+		l, err := Acquire(path, time.Second)
+		if err != nil {
+			os.Exit(1)
+		}
+		os.Stdout.WriteString("locked\n")
+		io.Copy(io.Discard, os.Stdin)
+		l.Release()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAcquire_NotReentrant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	outer, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Acquire(path, 200*time.Millisecond)
+	var held *HeldError
+	if !errors.As(err, &held) || held.PID != os.Getpid() {
+		t.Fatalf("expected HeldError with pid %d, got %v", os.Getpid(), err)
+	}
+	if err = outer.Release(); err != nil {
+		t.Fatal(err)
+	}
+	inner, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner.Release()
+}
+
+func TestAcquire_HeldByOtherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), envHoldLock+"="+path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer stdin.Close()
+	if line, _ := bufio.NewReader(stdout).ReadString('\n'); line != "locked\n" {
+		t.Fatalf("helper failed to lock: %q", line)
+	}
+
+	_, err = Acquire(path, 200*time.Millisecond)
+	var held *HeldError
+	if !errors.As(err, &held) || held.PID != cmd.Process.Pid {
+		t.Fatalf("expected HeldError with pid %d, got %v", cmd.Process.Pid, err)
+	}
+
+	// 持有者进程退出后锁由系统释放
+	if err = cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Acquire(path, 5*time.Second)
+	if err != nil {
+		t.Fatalf("lock should be released when its owner exits: %v", err)
+	}
+	l.Release()
+}
+
+func TestAcquire_LeftoverFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	// 旧版本留下的锁文件只有 PID，没有加锁，不应阻塞
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Acquire(path, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release()
+	if got := owner(path); got != os.Getpid() {
+		t.Errorf("lock owner = %d, want %d", got, os.Getpid())
+	}
+}
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	holder, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		holder.Release()
+	}()
+	l, err := Acquire(path, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Release()
+}
+
+func TestAcquire_Goroutines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	var inside, overlaps atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			l, err := Acquire(path, 10*time.Second)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if inside.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(20 * time.Millisecond)
+			inside.Add(-1)
+			l.Release()
+		})
+	}
+	wg.Wait()
+	if n := overlaps.Load(); n > 0 {
+		t.Errorf("%d goroutines held the lock at the same time", n)
+	}
+}
@@ -0,0 +1,132 @@
+package matrix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/the-yex/gvm/internal/shell"
+)
+
+// Target 是矩阵中的一个版本及运行命令时使用的环境变量
+type Target struct {
+	Version string
+	GoRoot  string
+	Env     []string
+}
+
+// SplitVersions 将 --versions 的各个值按逗号拆分为版本列表。
+// 逗号在版本约束中表示"并且"，因此紧跟在约束之后、以比较符开头的一项会合并到前一个约束中：
+// "1.21,1.22,latest" 拆分为三项，">=1.21,<1.23" 保持为一个约束。
+func SplitVersions(values []string) []string {
+	var specs []string
+	for _, value := range values {
+		merge := false
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			constraint := strings.ContainsAny(part[:1], "<>=!~^")
+			if merge && constraint {
+				specs[len(specs)-1] += "," + part
+				continue
+			}
+			specs = append(specs, part)
+			merge = constraint
+		}
+	}
+	return specs
+}
+
+// Result 记录一个版本的运行结果
+type Result struct {
+	Version  string        `json:"version"`
+	GoRoot   string        `json:"goroot"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"-"`
+	Seconds  float64       `json:"duration_seconds"`
+	Output   string        `json:"output"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Passed 报告该版本是否运行成功
+func (r Result) Passed() bool {
+	return r.ExitCode == 0 && r.Error == ""
+}
+
+// Options 控制矩阵的运行方式
+type Options struct {
+	// Parallel 为 true 时所有版本同时运行
+	Parallel bool
+	// Stream 不为空时输出每个版本的运行日志：串行时实时输出，并行时在该版本结束后整段输出
+	Stream io.Writer
+}
+
+// Run 在每个版本下运行命令，结果顺序与 targets 一致
+func Run(targets []Target, name string, args []string, opts Options) []Result {
+	results := make([]Result, len(targets))
+	if !opts.Parallel {
+		for i, target := range targets {
+			results[i] = runOne(target, name, args, opts.Stream)
+		}
+		return results
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for i, target := range targets {
+		wg.Go(func() {
+			results[i] = runOne(target, name, args, nil)
+			if opts.Stream != nil {
+				mu.Lock()
+				writeBlock(opts.Stream, results[i])
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+func runOne(target Target, name string, args []string, stream io.Writer) Result {
+	var output bytes.Buffer
+	writer := io.Writer(&output)
+	if stream != nil {
+		fmt.Fprintf(stream, "=== go%s\n", target.Version)
+		writer = io.MultiWriter(&output, stream)
+	}
+
+	result := Result{Version: target.Version, GoRoot: target.GoRoot}
+	start := time.Now()
+	if path, err := shell.LookPath(name, shell.Getenv(target.Env, "PATH")); err != nil {
+		result.ExitCode, result.Error = 127, err.Error()
+	} else {
+		cmd := exec.Command(path, args...)
+		cmd.Env = target.Env
+		cmd.Stdout, cmd.Stderr = writer, writer
+		if result.ExitCode, err = shell.ExitCode(cmd.Run()); err != nil {
+			result.Error = err.Error()
+		}
+	}
+	result.Duration = time.Since(start)
+	result.Seconds = result.Duration.Round(time.Millisecond).Seconds()
+	result.Output = output.String()
+	if stream != nil && result.Error != "" {
+		fmt.Fprintln(stream, result.Error)
+	}
+	return result
+}
+
+func writeBlock(w io.Writer, r Result) {
+	fmt.Fprintf(w, "=== go%s\n%s", r.Version, r.Output)
+	if r.Error != "" {
+		fmt.Fprintln(w, r.Error)
+	}
+}
@@ -0,0 +1,154 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func shTargets(t *testing.T, versions ...string) []Target {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	targets := make([]Target, 0, len(versions))
+	for _, v := range versions {
+		targets = append(targets, Target{
+			Version: v,
+			GoRoot:  "/sdk/go" + v,
+			Env:     []string{"PATH=" + os.Getenv("PATH"), "GO_VERSION=" + v},
+		})
+	}
+	return targets
+}
+
+// script 在 1.22 下失败，其余版本成功
+const script = `echo "running $GO_VERSION"; [ "$GO_VERSION" != "1.22.0" ] || exit 3`
+
+func TestRun(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		targets := shTargets(t, "1.21.0", "1.22.0", "1.23.1")
+		var stream bytes.Buffer
+		results := Run(targets, "sh", []string{"-c", script}, Options{Parallel: parallel, Stream: &stream})
+
+		if len(results) != len(targets) {
+			t.Fatalf("parallel=%v: got %d results", parallel, len(results))
+		}
+		for i, r := range results {
+			if r.Version != targets[i].Version {
+				t.Errorf("parallel=%v: result %d is %s, want %s", parallel, i, r.Version, targets[i].Version)
+			}
+			if want := "running " + r.Version + "\n"; r.Output != want {
+				t.Errorf("parallel=%v: output = %q, want %q", parallel, r.Output, want)
+			}
+			if !strings.Contains(stream.String(), "=== go"+r.Version+"\nrunning "+r.Version) {
+				t.Errorf("parallel=%v: stream missing block for %s:\n%s", parallel, r.Version, stream.String())
+			}
+		}
+		if results[1].ExitCode != 3 || results[1].Passed() {
+			t.Errorf("parallel=%v: 1.22.0 exit = %d, want 3", parallel, results[1].ExitCode)
+		}
+		if !results[0].Passed() || !results[2].Passed() {
+			t.Errorf("parallel=%v: expected 1.21.0 and 1.23.1 to pass", parallel)
+		}
+		if Failed(results) != 1 {
+			t.Errorf("parallel=%v: Failed = %d, want 1", parallel, Failed(results))
+		}
+	}
+}
+
+func TestRun_CommandNotFound(t *testing.T) {
+	results := Run(shTargets(t, "1.21.0"), "gvm-no-such-command", nil, Options{})
+	if results[0].ExitCode != 127 || results[0].Error == "" {
+		t.Errorf("got exit %d, error %q", results[0].ExitCode, results[0].Error)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	results := Run(shTargets(t, "1.21.0", "1.22.0"), "sh", []string{"-c", script}, Options{})
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, Table, "sh", results); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"VERSION", "go1.21.0  ok", "go1.22.0  FAIL", "1 passed, 1 failed"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("table missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, JSON, "sh -c", results); err != nil {
+			t.Fatal(err)
+		}
+		var report struct {
+			Command string   `json:"command"`
+			Failed  int      `json:"failed"`
+			Results []Result `json:"results"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Command != "sh -c" || report.Failed != 1 || len(report.Results) != 2 || report.Results[1].ExitCode != 3 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, JUnit, "sh -c", results); err != nil {
+			t.Fatal(err)
+		}
+		var doc junitSuites
+		if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Tests != 2 || doc.Failures != 1 || len(doc.Suites) != 1 {
+			t.Fatalf("unexpected document: %+v", doc)
+		}
+		cases := doc.Suites[0].Cases
+		if cases[0].Name != "go1.21.0" || cases[0].Failure != nil {
+			t.Errorf("case 0 = %+v", cases[0])
+		}
+		if cases[1].Failure == nil || cases[1].Failure.Message != "exit code 3" || !strings.Contains(cases[1].Failure.Body, "running 1.22.0") {
+			t.Errorf("case 1 = %+v", cases[1])
+		}
+	})
+}
+
+func TestSplitVersions(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   []string
+	}{
+		{[]string{"1.21,1.22,latest"}, []string{"1.21", "1.22", "latest"}},
+		{[]string{">=1.21,<1.23"}, []string{">=1.21,<1.23"}},
+		{[]string{"1.20", ">=1.21, <1.23", "latest"}, []string{"1.20", ">=1.21,<1.23", "latest"}},
+		{[]string{"1.20,>=1.22,!=1.22.1,1.23"}, []string{"1.20", ">=1.22,!=1.22.1", "1.23"}},
+		// 不同的 --versions 之间不会合并
+		{[]string{">=1.21", "<1.23"}, []string{">=1.21", "<1.23"}},
+		{[]string{" , "}, nil},
+	}
+	for _, tt := range tests {
+		if got := SplitVersions(tt.values); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitVersions(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if f, err := ParseFormat("junit"); err != nil || f != JUnit {
+		t.Errorf("ParseFormat(junit) = %q, %v", f, err)
+	}
+}
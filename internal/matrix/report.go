@@ -0,0 +1,145 @@
+package matrix
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Format 是矩阵结果的输出格式
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	JUnit Format = "junit"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case Table, JSON, JUnit:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("invalid output format %q, must be table | json | junit", s)
+	}
+}
+
+// Write 按 format 输出结果，command 为运行的命令行，用于 JSON 与 JUnit 报告
+func Write(w io.Writer, format Format, command string, results []Result) error {
+	switch format {
+	case JSON:
+		return writeJSON(w, command, results)
+	case JUnit:
+		return writeJUnit(w, command, results)
+	default:
+		return writeTable(w, results)
+	}
+}
+
+// Failed 返回失败的版本数量
+func Failed(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if !r.Passed() {
+			n++
+		}
+	}
+	return n
+}
+
+func writeTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATUS\tEXIT\tDURATION")
+	for _, r := range results {
+		status := "ok"
+		if !r.Passed() {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "go%s\t%s\t%d\t%s\n", r.Version, status, r.ExitCode, r.Duration.Round(time.Millisecond))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed\n", len(results)-Failed(results), Failed(results))
+	return err
+}
+
+func writeJSON(w io.Writer, command string, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Command string   `json:"command"`
+		Passed  int      `json:"passed"`
+		Failed  int      `json:"failed"`
+		Results []Result `json:"results"`
+	}{command, len(results) - Failed(results), Failed(results), results})
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     float64     `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit 每个版本作为一个 testcase，失败时输出作为 failure 内容
+func writeJUnit(w io.Writer, command string, results []Result) error {
+	suite := junitSuite{Name: command, Tests: len(results), Failures: Failed(results)}
+	for _, r := range results {
+		c := junitCase{Name: "go" + r.Version, Classname: "gvm.matrix", Time: r.Seconds}
+		if r.Passed() {
+			c.SystemOut = r.Output
+		} else {
+			message := fmt.Sprintf("exit code %d", r.ExitCode)
+			if r.Error != "" {
+				message = r.Error
+			}
+			c.Failure = &junitFailure{Message: message, Body: r.Output}
+		}
+		suite.Time += r.Seconds
+		suite.Cases = append(suite.Cases, c)
+	}
+	doc := junitSuites{
+		Name:     "gvm matrix",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
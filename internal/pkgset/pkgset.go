@@ -0,0 +1,172 @@
+package pkgset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+)
+
+// Default 是开启 pkgset_isolation 后每个版本默认使用的 pkgset
+const Default = "default"
+
+// activeFile 记录版本当前使用的 pkgset 名称
+const activeFile = ".active"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Pkgset 是某个 Go 版本下独立的 GOPATH，go install 的工具安装在其 bin 目录中
+type Pkgset struct {
+	Version string // 版本目录名，如 go1.21.5
+	Name    string
+}
+
+// Dir 返回 pkgset 的 GOPATH
+func (p Pkgset) Dir() string {
+	return filepath.Join(consts.PKGSET_DIR, p.Version, p.Name)
+}
+
+// Bin 返回 pkgset 的 GOBIN
+func (p Pkgset) Bin() string {
+	return filepath.Join(p.Dir(), "bin")
+}
+
+func (p Pkgset) String() string {
+	return p.Version + "@" + p.Name
+}
+
+// validate 检查名称合法且 Dir 位于 PKGSET_DIR 下的版本目录中，
+// 避免 ".." 之类的名称让 Delete 删除 pkgsets 之外的目录
+func (p Pkgset) validate() error {
+	if !validName.MatchString(p.Name) || p.Name == "." || p.Name == ".." {
+		return fmt.Errorf("invalid pkgset name %q: must contain only letters, digits, '.', '_' or '-'", p.Name)
+	}
+	rel, err := filepath.Rel(consts.PKGSET_DIR, filepath.Clean(p.Dir()))
+	if err != nil || rel != filepath.Join(p.Version, p.Name) || strings.ContainsAny(p.Version, `/\`) ||
+		p.Version == "" || p.Version == "." || p.Version == ".." {
+		return fmt.Errorf("invalid pkgset %s", p)
+	}
+	return nil
+}
+
+// Active 返回版本当前使用的 pkgset：优先使用 gvm pkgset use 选择的，
+// 否则在开启 pkgset_isolation 时使用 Default，都没有时返回 false，即不隔离 GOPATH
+func Active(versionDir string) (Pkgset, bool) {
+	data, err := os.ReadFile(filepath.Join(consts.PKGSET_DIR, versionDir, activeFile))
+	if name := strings.TrimSpace(string(data)); err == nil && name != "" {
+		return Pkgset{Version: versionDir, Name: name}, true
+	}
+	if viper.GetBool(consts.CONFIG_PKGSET_ISOLATION) {
+		return Pkgset{Version: versionDir, Name: Default}, true
+	}
+	return Pkgset{}, false
+}
+
+// List 返回版本下所有的 pkgset 名称
+func List(versionDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(consts.PKGSET_DIR, versionDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Create 创建 pkgset 目录
+func Create(p Pkgset) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(p.Dir()); err == nil {
+		return fmt.Errorf("pkgset %s already exists", p)
+	}
+	return os.MkdirAll(p.Bin(), 0755)
+}
+
+// Use 将 pkgset 设为版本当前使用的 pkgset，Default 不存在时自动创建
+func Use(p Pkgset) error {
+	if err := Ensure(p); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(consts.PKGSET_DIR, p.Version, activeFile), []byte(p.Name+"\n"), 0644)
+}
+
+// Off 取消版本选择的 pkgset
+func Off(versionDir string) error {
+	err := os.Remove(filepath.Join(consts.PKGSET_DIR, versionDir, activeFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Ensure 确保 pkgset 存在：Default 按需创建，其它 pkgset 需要先 Create
+func Ensure(p Pkgset) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(p.Dir()); err == nil {
+		return nil
+	}
+	if p.Name != Default {
+		return fmt.Errorf("pkgset %s not found, create it with \"gvm pkgset create %s\"", p, p.Name)
+	}
+	return os.MkdirAll(p.Bin(), 0755)
+}
+
+// Delete 删除 pkgset，正在使用时一并取消选择
+func Delete(p Pkgset) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(p.Dir()); err != nil {
+		return fmt.Errorf("pkgset %s not found", p)
+	}
+	if active, ok := Active(p.Version); ok && active == p {
+		if err := Off(p.Version); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(p.Dir())
+}
+
+// Link 将 GOPATH_LINK 指向全局版本 versionDir 当前使用的 pkgset，没有 pkgset 时删除软链接。
+// 与 GO_ROOT 一样先创建临时软链接再重命名，切换过程中不会出现软链接缺失的情况。
+func Link(versionDir string) error {
+	if info, err := os.Lstat(consts.GOPATH_LINK); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("%s exists and is not a symlink, remove it to use pkgsets", consts.GOPATH_LINK)
+	}
+	p, ok := Active(versionDir)
+	if !ok {
+		if err := os.Remove(consts.GOPATH_LINK); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := Ensure(p); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.switch-%d", consts.GOPATH_LINK, os.Getpid())
+	os.Remove(tmp)
+	if err := os.Symlink(p.Dir(), tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, consts.GOPATH_LINK); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package pkgset
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func usePkgsetDir(t *testing.T) {
+	t.Helper()
+	testutil.GvmHome(t)
+	t.Cleanup(viper.Reset)
+}
+
+func TestCreateUseDelete(t *testing.T) {
+	usePkgsetDir(t)
+	const ver = "go1.22.0"
+	tools := Pkgset{Version: ver, Name: "tools"}
+
+	if _, ok := Active(ver); ok {
+		t.Fatal("no pkgset should be active by default")
+	}
+	if err := Use(tools); err == nil {
+		t.Error("expected error using a pkgset that does not exist")
+	}
+	if err := Create(tools); err != nil {
+		t.Fatal(err)
+	}
+	if err := Create(tools); err == nil {
+		t.Error("expected error creating an existing pkgset")
+	}
+	if err := Create(Pkgset{Version: ver, Name: "../escape"}); err == nil {
+		t.Error("expected error for invalid name")
+	}
+	if _, err := os.Stat(tools.Bin()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Use(tools); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := Active(ver); !ok || p != tools {
+		t.Errorf("Active = %v, %v, want %v", p, ok, tools)
+	}
+	// 默认 pkgset 在使用时自动创建
+	if err := Use(Pkgset{Version: ver, Name: Default}); err != nil {
+		t.Fatal(err)
+	}
+	names, err := List(ver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{Default, "tools"}) {
+		t.Errorf("List = %v", names)
+	}
+
+	if err = Delete(Pkgset{Version: ver, Name: Default}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Active(ver); ok {
+		t.Error("deleting the active pkgset should deselect it")
+	}
+	if err = Delete(Pkgset{Version: ver, Name: Default}); err == nil {
+		t.Error("expected error deleting a missing pkgset")
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	usePkgsetDir(t)
+	// pkgsets 目录旁的文件不能被删除
+	sentinel := filepath.Join(filepath.Dir(consts.PKGSET_DIR), "config.yaml")
+	if err := os.WriteFile(sentinel, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Create(Pkgset{Version: "go1.22.0", Name: "tools"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Pkgset{
+		{Version: "go1.22.0", Name: ".."},
+		{Version: "go1.22.0", Name: "../.."},
+		{Version: "go1.22.0", Name: "."},
+		{Version: "go1.22.0", Name: "tools/../.."},
+		{Version: "..", Name: "tools"},
+		{Version: "../..", Name: "go1.22.0"},
+	} {
+		if err := Delete(p); err == nil {
+			t.Errorf("Delete(%q, %q) should fail", p.Version, p.Name)
+		}
+		if err := Use(p); err == nil {
+			t.Errorf("Use(%q, %q) should fail", p.Version, p.Name)
+		}
+		if err := Ensure(p); err == nil {
+			t.Errorf("Ensure(%q, %q) should fail", p.Version, p.Name)
+		}
+	}
+	if _, err := os.Stat(sentinel); err != nil {
+		t.Errorf("file outside pkgsets removed: %v", err)
+	}
+	if _, err := os.Stat(Pkgset{Version: "go1.22.0", Name: "tools"}.Dir()); err != nil {
+		t.Errorf("pkgset removed: %v", err)
+	}
+}
+
+func TestActive_Isolation(t *testing.T) {
+	usePkgsetDir(t)
+	viper.Set(consts.CONFIG_PKGSET_ISOLATION, true)
+	p, ok := Active("go1.21.5")
+	if !ok || p.Name != Default {
+		t.Errorf("Active = %v, %v, want default pkgset", p, ok)
+	}
+}
+
+func TestLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	usePkgsetDir(t)
+	tools := Pkgset{Version: "go1.22.0", Name: "tools"}
+	if err := Create(tools); err != nil {
+		t.Fatal(err)
+	}
+	if err := Use(tools); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Link(tools.Version); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.Readlink(consts.GOPATH_LINK); err != nil || got != tools.Dir() {
+		t.Errorf("GOPATH_LINK -> %s, %v, want %s", got, err, tools.Dir())
+	}
+
+	// 切换到没有 pkgset 的版本时删除软链接
+	if err := Link("go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(consts.GOPATH_LINK); !os.IsNotExist(err) {
+		t.Errorf("GOPATH_LINK should be removed, got %v", err)
+	}
+
+	// 不会覆盖用户自己创建的目录
+	if err := os.Mkdir(consts.GOPATH_LINK, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Link(tools.Version); err == nil {
+		t.Error("expected error when GOPATH_LINK is a directory")
+	}
+}
@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/alias"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Profile 是附加在某个版本或别名上的环境变量
+type Profile struct {
+	// Version 可以是具体版本（1.22.3）、次版本（1.22，匹配所有 1.22.x）或别名
+	Version string `mapstructure:"version"`
+	// Env 中每一项为 KEY=VALUE
+	Env []string `mapstructure:"env"`
+}
+
+// reserved 是由 gvm 管理、不能在 envs 中设置的环境变量
+var reserved = []string{"GOROOT", "PATH"}
+
+// List 返回配置文件 envs 中的环境变量配置
+func List() ([]Profile, error) {
+	var profiles []Profile
+	if err := viper.UnmarshalKey(consts.CONFIG_ENVS, &profiles); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", consts.CONFIG_ENVS, err)
+	}
+	for _, p := range profiles {
+		for _, kv := range p.Env {
+			key, _, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("invalid %q config: %q of version %s must be KEY=VALUE", consts.CONFIG_ENVS, kv, p.Version)
+			}
+			if slices.Contains(reserved, key) {
+				return nil, fmt.Errorf("invalid %q config: %s is managed by gvm and cannot be set for version %s", consts.CONFIG_ENVS, key, p.Version)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// For 返回适用于版本 v 的环境变量，多个配置匹配时按配置顺序合并，后面的覆盖前面的
+func For(v *version.Version) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	profiles, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var env []string
+	for _, p := range profiles {
+		if !matches(p.Version, v) {
+			continue
+		}
+		for _, kv := range p.Env {
+			key, value, _ := strings.Cut(kv, "=")
+			env = shell.WithEnv(env, key, value)
+		}
+	}
+	return env, nil
+}
+
+// matches 报告 spec 是否指向版本 v：具体版本与预发布版本精确匹配，1.22 这样的次版本匹配所有 1.22.x
+func matches(spec string, v *version.Version) bool {
+	spec = strings.TrimPrefix(strings.TrimSpace(alias.Resolve(spec)), "go")
+	target, err := version.NewGoVersion(spec)
+	if err != nil {
+		return false
+	}
+	if target.Prerelease() != "" || strings.Count(spec, ".") >= 2 {
+		return target.Equal(v)
+	}
+	return v.Major() == target.Major() && v.Minor() == target.Minor()
+}
@@ -0,0 +1,69 @@
+package profile
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/testutil"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+func mustVersion(t *testing.T, v string) *version.Version {
+	t.Helper()
+	ver, err := version.NewGoVersion(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ver
+}
+
+func TestFor(t *testing.T) {
+	testutil.Config(t, `
+aliases:
+  - name: legacy
+    version: "1.19"
+envs:
+  - version: "1.22"
+    env:
+      - GOEXPERIMENT=rangefunc
+      - CGO_ENABLED=0
+  - version: 1.22.3
+    env:
+      - CGO_ENABLED=1
+      - GOPROXY=https://proxy.example.com,direct
+  - version: legacy
+    env:
+      - GOFLAGS=-mod=mod
+`)
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"1.22.1", []string{"GOEXPERIMENT=rangefunc", "CGO_ENABLED=0"}},
+		{"1.22.3", []string{"GOEXPERIMENT=rangefunc", "CGO_ENABLED=1", "GOPROXY=https://proxy.example.com,direct"}},
+		{"1.19.13", []string{"GOFLAGS=-mod=mod"}},
+		{"1.21.0", nil},
+	}
+	for _, tt := range tests {
+		got, err := For(mustVersion(t, tt.version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("For(%s) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestList_Invalid(t *testing.T) {
+	for _, config := range []string{
+		"envs:\n  - version: \"1.22\"\n    env:\n      - GOFLAGS\n",
+		"envs:\n  - version: \"1.22\"\n    env:\n      - PATH=/tmp\n",
+		"envs:\n  - version: \"1.22\"\n    env:\n      - GOROOT=/opt/go\n",
+	} {
+		testutil.Config(t, config)
+		if _, err := List(); err == nil {
+			t.Errorf("expected error for config:\n%s", config)
+		}
+	}
+}
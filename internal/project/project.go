@@ -0,0 +1,175 @@
+package project
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/the-yex/gvm/internal/version"
+)
+
+const (
+	GoVersionFile = ".go-version"
+	GvmrcFile     = ".gvmrc"
+	GoWorkFile    = "go.work"
+	GoModFile     = "go.mod"
+)
+
+// ErrNotFound 表示从当前目录到根目录都没有找到锁定版本的文件
+var ErrNotFound = errors.New("no .go-version, .gvmrc, go.work or go.mod found")
+
+// Pin 是项目锁定的 Go 版本及决定该版本的文件。
+// Version 可能是具体的发行版本（1.22.3、1.21rc1），也可能是 1.21 这样的语言版本，
+// 后者表示该次版本中最新的补丁版本。
+type Pin struct {
+	Version   string
+	File      string
+	Directive string // go.mod/go.work 中决定版本的指令：go 或 toolchain
+}
+
+func (p Pin) String() string {
+	if p.Directive != "" {
+		return fmt.Sprintf("%s (from %s directive in %s)", p.Version, p.Directive, p.File)
+	}
+	return fmt.Sprintf("%s (from %s)", p.Version, p.File)
+}
+
+// Release 报告 Version 是否是具体的发行版本；1.21 这样的语言版本返回 false
+func (p Pin) Release() bool {
+	return isRelease(p.Version)
+}
+
+// tiers 按优先级排列：显式的版本文件优先于 go.work，go.work 优先于 go.mod。
+// 每一层都会从当前目录一直向上查找，因此仓库根目录的 .go-version 优先于子目录的 go.mod。
+// optional 的文件中没有版本时视为该文件未锁定版本，继续向上查找；否则返回错误。
+var tiers = []struct {
+	files    []string
+	parse    func(data []byte) (Pin, bool)
+	optional bool
+}{
+	{files: []string{GoVersionFile, GvmrcFile}, parse: parseVersionFile},
+	{files: []string{GoWorkFile}, parse: parseModFile, optional: true},
+	{files: []string{GoModFile}, parse: parseModFile, optional: true},
+}
+
+// Find 从 dir 开始向上查找项目锁定的 Go 版本
+func Find(dir string) (Pin, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Pin{}, err
+	}
+	for _, tier := range tiers {
+		for d := dir; ; d = filepath.Dir(d) {
+			for _, name := range tier.files {
+				file := filepath.Join(d, name)
+				data, err := os.ReadFile(file)
+				if err != nil {
+					continue
+				}
+				pin, ok := tier.parse(data)
+				if !ok && tier.optional {
+					// 没有 go 指令的 go.mod（go mod init 早于 1.12 时生成）不锁定版本
+					continue
+				}
+				if !ok {
+					return Pin{}, fmt.Errorf("no go version found in %s", file)
+				}
+				pin.File = file
+				return pin, nil
+			}
+			if parent := filepath.Dir(d); parent == d {
+				break
+			}
+		}
+	}
+	return Pin{}, ErrNotFound
+}
+
+// WriteVersionFile 在 dir 下写入 .go-version
+func WriteVersionFile(dir, version string) (string, error) {
+	file := filepath.Join(dir, GoVersionFile)
+	return file, os.WriteFile(file, []byte(strings.TrimSpace(version)+"\n"), 0644)
+}
+
+// parseVersionFile 读取 .go-version/.gvmrc 中第一个非空、非注释的行
+func parseVersionFile(data []byte) (Pin, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return Pin{Version: strings.TrimPrefix(line, "go")}, true
+	}
+	return Pin{}, false
+}
+
+var (
+	goDirectiveReg        = regexp.MustCompile(`(?m)^go\s+(\d+(?:\.\d+){0,2}(?:(?:alpha|beta|rc)\d+)?)\s*(?:$|//.*)`)
+	toolchainDirectiveReg = regexp.MustCompile(`(?m)^toolchain\s+(\S+)\s*(?:$|//.*)`)
+)
+
+// parseModFile 按 go.mod/go.work 的语义解析版本：
+// go 指令给出最低版本，toolchain 指令给出建议使用的工具链，二者取较新的一个。
+// 与 go 命令一致，toolchain 低于 go 指令时被忽略，toolchain default 表示未指定。
+func parseModFile(data []byte) (Pin, bool) {
+	match := goDirectiveReg.FindSubmatch(data)
+	if len(match) < 2 {
+		return Pin{}, false
+	}
+	pin := Pin{Version: string(match[1]), Directive: "go"}
+
+	match = toolchainDirectiveReg.FindSubmatch(data)
+	if len(match) < 2 {
+		return pin, true
+	}
+	toolchain, ok := parseToolchain(string(match[1]))
+	if ok && compareGoVersions(toolchain, pin.Version) >= 0 {
+		return Pin{Version: toolchain, Directive: "toolchain"}, true
+	}
+	return pin, true
+}
+
+// parseToolchain 将 go1.22.3 或 go1.22.3-custom 这样的工具链名转换为版本号
+func parseToolchain(name string) (string, bool) {
+	if name == "default" || !strings.HasPrefix(name, "go") {
+		return "", false
+	}
+	v := strings.TrimPrefix(name, "go")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if _, err := version.NewGoVersion(v); err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// compareGoVersions 按 go 命令的规则比较版本：语言版本 1.21 < 1.21rc1 < 1.21.0
+func compareGoVersions(a, b string) int {
+	va, errA := version.NewGoVersion(a)
+	vb, errB := version.NewGoVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	// 语言版本 1.21 早于同一次版本的任何发行版本，包括 1.21rc1
+	if isRelease(a) != isRelease(b) && va.Major() == vb.Major() && va.Minor() == vb.Minor() {
+		if isRelease(a) {
+			return 1
+		}
+		return -1
+	}
+	return va.Compare(vb)
+}
+
+// isRelease 报告 v 是否是具体的发行版本：带补丁号或预发布标记
+func isRelease(v string) bool {
+	if strings.ContainsAny(v, "abcdefghijklmnopqrstuvwxyz") {
+		return true
+	}
+	return strings.Count(v, ".") >= 2
+}
@@ -0,0 +1,111 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	svc := filepath.Join(root, "services", "billing")
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.22.0\n\nuse ./services/billing\n")
+	writeFile(t, filepath.Join(svc, "go.mod"), "module billing\n\ngo 1.21 // minimum\n")
+
+	pin, err := Find(filepath.Join(svc, "internal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pin.Version != "1.22.0" || pin.File != filepath.Join(root, "go.work") {
+		t.Errorf("go.work should win over go.mod, got %s", pin)
+	}
+
+	writeFile(t, filepath.Join(svc, ".gvmrc"), "# billing\n\ngo1.20.3\n")
+	if pin, err = Find(svc); err != nil || pin.Version != "1.20.3" || pin.File != filepath.Join(svc, ".gvmrc") {
+		t.Errorf(".gvmrc should win, got %s, %v", pin, err)
+	}
+
+	// 同一目录下 .go-version 优先于 .gvmrc
+	if _, err = WriteVersionFile(svc, "1.23"); err != nil {
+		t.Fatal(err)
+	}
+	if pin, err = Find(svc); err != nil || pin.Version != "1.23" || pin.File != filepath.Join(svc, ".go-version") {
+		t.Errorf(".go-version should win, got %s, %v", pin, err)
+	}
+}
+
+func TestFind_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Find(dir); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	writeFile(t, filepath.Join(dir, ".go-version"), "# empty\n")
+	if _, err := Find(dir); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected parse error for empty .go-version, got %v", err)
+	}
+}
+
+func TestFind_NoGoDirective(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "legacy")
+	writeFile(t, filepath.Join(sub, "go.mod"), "module legacy\n\nrequire example.com/x v1.0.0\n")
+	if _, err := Find(sub); !errors.Is(err, ErrNotFound) {
+		t.Errorf("go.mod without go directive should not pin a version, got %v", err)
+	}
+
+	// 继续向上查找外层的 go.mod
+	writeFile(t, filepath.Join(root, "go.mod"), "module outer\n\ngo 1.21.5\n")
+	if pin, err := Find(sub); err != nil || pin.Version != "1.21.5" || pin.File != filepath.Join(root, "go.mod") {
+		t.Errorf("expected outer go.mod, got %s, %v", pin, err)
+	}
+}
+
+func TestParseModFile(t *testing.T) {
+	tests := []struct {
+		name, data    string
+		version, from string
+		release       bool
+	}{
+		{"language version", "module x\n\ngo 1.21\n", "1.21", "go", false},
+		{"release", "module x\n\ngo 1.21.0\n", "1.21.0", "go", true},
+		{"prerelease", "module x\n\ngo 1.22rc1\n", "1.22rc1", "go", true},
+		{"toolchain", "module x\n\ngo 1.21\n\ntoolchain go1.22.3\n", "1.22.3", "toolchain", true},
+		{"toolchain same minor", "go 1.21\ntoolchain go1.21.0\n", "1.21.0", "toolchain", true},
+		{"toolchain suffix", "go 1.21.0\ntoolchain go1.21.4-custom // vendored\n", "1.21.4", "toolchain", true},
+		{"older toolchain ignored", "go 1.22.1\ntoolchain go1.21.9\n", "1.22.1", "go", true},
+		{"toolchain default", "go 1.21.5\ntoolchain default\n", "1.21.5", "go", true},
+		{"go.work", "go 1.22.0\n\nuse (\n\t./a\n\t./b\n)\n", "1.22.0", "go", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pin, ok := parseModFile([]byte(tt.data))
+			if !ok {
+				t.Fatal("no version parsed")
+			}
+			if pin.Version != tt.version || pin.Directive != tt.from || pin.Release() != tt.release {
+				t.Errorf("got %+v release=%v, want %s from %s release=%v", pin, pin.Release(), tt.version, tt.from, tt.release)
+			}
+		})
+	}
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	ordered := []string{"1.20", "1.20.1", "1.21", "1.21rc1", "1.21rc2", "1.21.0", "1.21.3", "1.22"}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := ordered[i], ordered[i+1]
+		if compareGoVersions(a, b) >= 0 || compareGoVersions(b, a) <= 0 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+	}
+}
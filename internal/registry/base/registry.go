@@ -3,7 +3,9 @@ package base
 import (
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/the-yex/gvm/internal/transport"
 	"github.com/the-yex/gvm/internal/version"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -36,24 +38,32 @@ func NewBaseRegistry(mirrorUrl string, timeout time.Duration) (*Registry, error)
 	return r, nil
 }
 func (r *Registry) loadDocument(timeout time.Duration) error {
-	client := &http.Client{Timeout: timeout}
-	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	body, err := Fetch(r.url, timeout)
 	if err != nil {
-		return fmt.Errorf("invalid request for %s: %w", r.url, err)
+		return err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
+	defer body.Close()
 
-	resp, err := client.Do(req)
+	r.Doc, err = goquery.NewDocumentFromReader(body)
+	return err
+}
+
+// Fetch 请求 rawURL 并返回响应体，非 200 状态码视为错误，调用方负责关闭
+func Fetch(rawURL string, timeout time.Duration) (io.ReadCloser, error) {
+	req, err := transport.NewRequest(http.MethodGet, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request for %s: %w", rawURL, err)
+	}
+
+	resp, err := transport.Do(req, timeout)
 	if err != nil {
-		return fmt.Errorf("failed to fetch %s: %w", r.url, err)
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%s returned %s", r.url, resp.Status)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s", rawURL, resp.Status)
 	}
-
-	r.Doc, err = goquery.NewDocumentFromReader(resp.Body)
-	return err
+	return resp.Body, nil
 }
 
 func (r *Registry) StableVersions() (versions []*version.Version, err error) {
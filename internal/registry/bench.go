@@ -0,0 +1,154 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/the-yex/gvm/internal/transport"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// behindRatio 版本数量少于最多镜像的该比例时视为同步落后
+const behindRatio = 0.9
+
+// BenchResult 记录单个镜像的测速结果
+type BenchResult struct {
+	Mirror     Mirror
+	Latency    time.Duration    // 索引获取并解析的耗时
+	Throughput float64          // 样本下载速度（字节/秒），0 表示未能测速
+	Local      bool             // 安装包在本地磁盘上，不经过网络，不测速
+	Versions   int              // 解析出的版本数量
+	Latest     *version.Version // 镜像上的最新版本
+	Behind     bool             // 版本明显落后于其它镜像
+	Err        error
+}
+
+// Bench 并发测试所有镜像：获取索引的延迟，以及对当前平台最新安装包发起 Range 请求的下载速度。
+// 返回结果按推荐程度排序：本地镜像排在网络镜像之前，失败的镜像排在最后。
+func Bench(mirrors []Mirror, timeout time.Duration, sampleBytes int64) []BenchResult {
+	results := make([]BenchResult, len(mirrors))
+	wg := sync.WaitGroup{}
+	for i, mirror := range mirrors {
+		wg.Go(func() {
+			results[i] = benchMirror(mirror, timeout, sampleBytes)
+		})
+	}
+	wg.Wait()
+
+	markBehind(results)
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Behind != b.Behind {
+			return !a.Behind
+		}
+		if a.Local != b.Local {
+			return a.Local
+		}
+		if a.Throughput != b.Throughput {
+			return a.Throughput > b.Throughput
+		}
+		return a.Latency < b.Latency
+	})
+	return results
+}
+
+func benchMirror(mirror Mirror, timeout time.Duration, sampleBytes int64) BenchResult {
+	result := BenchResult{Mirror: mirror}
+	start := time.Now()
+	rg, err := newRegistry(mirror, timeout)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	versions, err := rg.AllVersions()
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Versions = len(versions)
+	if len(versions) == 0 {
+		result.Err = fmt.Errorf("no versions found")
+		return result
+	}
+
+	latest, err := version.NewFinder(versions).Find(version.Latest)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Latest = latest
+	artifact, err := latest.FindArtifact()
+	if err != nil {
+		return result
+	}
+	if !strings.HasPrefix(artifact.URL, "http://") && !strings.HasPrefix(artifact.URL, "https://") {
+		result.Local = true // 本地镜像无需测速
+		return result
+	}
+	result.Throughput, err = sampleThroughput(artifact.URL, timeout, sampleBytes)
+	if err != nil {
+		result.Err = err
+	}
+	return result
+}
+
+// sampleThroughput 通过 Range 请求下载安装包的前 sampleBytes 字节并计算速度
+func sampleThroughput(rawURL string, timeout time.Duration, sampleBytes int64) (float64, error) {
+	req, err := transport.NewRequest(http.MethodGet, rawURL)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", sampleBytes-1))
+
+	start := time.Now()
+	resp, err := transport.Do(req, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("sample download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("%s returned %s", rawURL, resp.Status)
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, sampleBytes))
+	elapsed := time.Since(start).Seconds()
+	if err != nil && n == 0 {
+		return 0, fmt.Errorf("sample download failed: %w", err)
+	}
+	if elapsed <= 0 {
+		elapsed = 0.001
+	}
+	return float64(n) / elapsed, nil
+}
+
+// markBehind 标记最新版本或版本数量明显落后于其它镜像的结果
+func markBehind(results []BenchResult) {
+	var (
+		newest      *version.Version
+		maxVersions int
+	)
+	for _, r := range results {
+		if r.Err != nil || r.Latest == nil {
+			continue
+		}
+		if newest == nil || r.Latest.GreaterThan(newest) {
+			newest = r.Latest
+		}
+		maxVersions = max(maxVersions, r.Versions)
+	}
+	for i := range results {
+		r := &results[i]
+		if r.Err != nil || r.Latest == nil {
+			continue
+		}
+		r.Behind = r.Latest.LessThan(newest) || float64(r.Versions) < float64(maxVersions)*behindRatio
+	}
+}
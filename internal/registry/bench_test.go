@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFeedServer(t *testing.T, versions ...string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".tar.gz") {
+			if r.Header.Get("Range") == "" {
+				t.Errorf("expected ranged request for %s", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(make([]byte, 1024))
+			return
+		}
+		entries := make([]string, 0, len(versions))
+		for _, v := range versions {
+			entries = append(entries, fmt.Sprintf(
+				`{"version": "go%s", "stable": true, "files": [{"filename": "go%s.%s-%s.tar.gz", "os": %q, "arch": %q, "kind": "archive"}]}`,
+				v, v, runtime.GOOS, runtime.GOARCH, runtime.GOOS, runtime.GOARCH))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(entries, ","))
+	}))
+}
+
+func TestBench(t *testing.T) {
+	fresh := newFeedServer(t, "1.22.5", "1.22.4", "1.21.12")
+	defer fresh.Close()
+	stale := newFeedServer(t, "1.22.4", "1.21.12")
+	defer stale.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	defer down.Close()
+
+	// 与 fresh 同步的本地镜像不经过网络，排在第一
+	offline := t.TempDir()
+	for _, v := range []string{"1.22.5", "1.22.4", "1.21.12"} {
+		name := fmt.Sprintf("go%s.%s-%s.tar.gz", v, runtime.GOOS, runtime.GOARCH)
+		if err := os.WriteFile(filepath.Join(offline, name), []byte("archive"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := Bench([]Mirror{
+		{Name: "down", URL: down.URL + "/dl/", Parser: ParserJSON},
+		{Name: "stale", URL: stale.URL + "/dl/", Parser: ParserJSON},
+		{Name: "fresh", URL: fresh.URL + "/dl/", Parser: ParserJSON},
+		{Name: "offline", URL: offline, Parser: ParserLocal},
+	}, 5*time.Second, 512)
+
+	got := make([]string, 0, len(results))
+	for _, r := range results {
+		got = append(got, r.Mirror.Name)
+	}
+	if strings.Join(got, ",") != "offline,fresh,stale,down" {
+		t.Fatalf("ranking = %v", got)
+	}
+	if !results[0].Local || results[0].Behind || results[0].Err != nil {
+		t.Errorf("offline result = %+v", results[0])
+	}
+	if results[1].Local || results[1].Behind || results[1].Throughput <= 0 || results[1].Versions != 3 {
+		t.Errorf("fresh result = %+v", results[1])
+	}
+	if !results[2].Behind {
+		t.Errorf("stale mirror should be flagged as behind")
+	}
+	if results[3].Err == nil {
+		t.Errorf("down mirror should report an error")
+	}
+}
@@ -0,0 +1,69 @@
+package directory
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/the-yex/gvm/internal/registry/base"
+	"github.com/the-yex/gvm/internal/registry/internal"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Registry 解析任意包含 Go 安装包链接的普通目录页（如 Artifactory、Apache 目录列表），
+// 只依赖 <a href> 链接，不解析文件大小等附加信息。
+type Registry struct {
+	base *base.Registry
+}
+
+func NewRegistry(mirrorUrl string, timeout time.Duration) (*Registry, error) {
+	baseRegistry, err := base.NewBaseRegistry(mirrorUrl, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{base: baseRegistry}, nil
+}
+
+func (r Registry) StableVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) UnstableVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) ArchivedVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) AllVersions() (versions []*version.Version, err error) {
+	anchors := r.base.Doc.Find("a[href]")
+	items := make([]*internal.GoFileItem, 0, anchors.Length())
+	seen := make(map[string]bool, anchors.Length())
+
+	anchors.Each(func(j int, anchor *goquery.Selection) {
+		href := anchor.AttrOr("href", "")
+		if strings.HasSuffix(href, "/") || strings.Contains(href, "?") {
+			return
+		}
+		fileName := path.Base(href)
+		if !strings.HasPrefix(fileName, "go") || seen[fileName] {
+			return
+		}
+		seen[fileName] = true
+
+		ref, err := r.base.Url.Parse(href)
+		if err != nil {
+			return
+		}
+		items = append(items, &internal.GoFileItem{
+			FileName: fileName,
+			URL:      ref.String(),
+		})
+	})
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return internal.Convert2Versions(items)
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"sort"
+
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// supportedMinors 官方下载页 stable 区只展示最近两个受支持的次版本
+const supportedMinors = 2
+
+// SplitReleases 按照官方下载页的规则把版本划分为 stable / unstable / archived：
+//   - stable: 最近两个次版本线上各自最新的稳定补丁版本
+//   - unstable: 比最新稳定版更新的预发布版本
+//   - archived: 其余所有版本
+//
+// isStable 为空时以是否带预发布标识判断稳定性。三个结果均按版本号降序排列。
+func SplitReleases(versions []*version.Version, isStable func(v *version.Version) bool) (stable, unstable, archived []*version.Version) {
+	if isStable == nil {
+		isStable = func(v *version.Version) bool { return v.Prerelease() == "" }
+	}
+	sorted := make([]*version.Version, len(versions))
+	copy(sorted, versions)
+	sort.Sort(sort.Reverse(version.Collection(sorted)))
+
+	var (
+		latestStable *version.Version
+		seenMinors   = make(map[[2]uint64]bool, supportedMinors)
+	)
+	for _, v := range sorted {
+		if !isStable(v) {
+			continue
+		}
+		if latestStable == nil {
+			latestStable = v
+		}
+		line := [2]uint64{v.Major(), v.Minor()}
+		if !seenMinors[line] && len(seenMinors) < supportedMinors {
+			seenMinors[line] = true
+			stable = append(stable, v)
+		}
+	}
+
+	for _, v := range sorted {
+		switch {
+		case isStable(v):
+			if !containsVersion(stable, v) {
+				archived = append(archived, v)
+			}
+		case latestStable == nil || v.GreaterThan(latestStable):
+			unstable = append(unstable, v)
+		default:
+			archived = append(archived, v)
+		}
+	}
+	return stable, unstable, archived
+}
+
+func containsVersion(versions []*version.Version, target *version.Version) bool {
+	for _, v := range versions {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
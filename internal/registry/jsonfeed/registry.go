@@ -0,0 +1,156 @@
+package jsonfeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/the-yex/gvm/internal/registry/base"
+	"github.com/the-yex/gvm/internal/registry/internal"
+	"github.com/the-yex/gvm/internal/utils"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Registry 解析官方下载页提供的 JSON 版本清单（?mode=json&include=all），
+// 不依赖 HTML 页面结构。
+type Registry struct {
+	url      *url.URL
+	releases []release
+}
+
+type release struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []file `json:"files"`
+}
+
+type file struct {
+	FileName string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"` // archive | installer | source
+}
+
+var kinds = map[string]version.Kind{
+	"archive":   version.ArchiveKind,
+	"installer": version.InstallerKind,
+	"source":    version.SourceKind,
+}
+
+// NewRegistry 创建一个新的 JSON Registry 实例，mirrorUrl 上已有的查询参数会被替换
+func NewRegistry(mirrorUrl string, timeout time.Duration) (*Registry, error) {
+	u, err := url.Parse(mirrorUrl)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = ""
+	feed := *u
+	feed.RawQuery = url.Values{"mode": {"json"}, "include": {"all"}}.Encode()
+
+	body, err := base.Fetch(feed.String(), timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	r := &Registry{url: u}
+	if err = json.NewDecoder(body).Decode(&r.releases); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", feed.String(), err)
+	}
+	return r, nil
+}
+
+// StableVersions 返回所有稳定版本
+func (r Registry) StableVersions() (versions []*version.Version, err error) {
+	stable, _, _, err := r.split()
+	return stable, err
+}
+
+// UnstableVersions 返回所有不稳定版本
+func (r Registry) UnstableVersions() (versions []*version.Version, err error) {
+	_, unstable, _, err := r.split()
+	return unstable, err
+}
+
+// ArchivedVersions 返回所有归档版本
+func (r Registry) ArchivedVersions() (versions []*version.Version, err error) {
+	_, _, archived, err := r.split()
+	return archived, err
+}
+
+// AllVersions 返回所有版本（稳定版、不稳定版和归档版）
+func (r Registry) AllVersions() (versions []*version.Version, err error) {
+	stable, unstable, archived, err := r.split()
+	if err != nil {
+		return nil, err
+	}
+	versions = make([]*version.Version, 0, len(stable)+len(unstable)+len(archived))
+	versions = append(versions, stable...)
+	versions = append(versions, unstable...)
+	versions = append(versions, archived...)
+	return versions, nil
+}
+
+func (r Registry) split() (stable, unstable, archived []*version.Version, err error) {
+	versions, stableSet, err := r.versions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stable, unstable, archived = internal.SplitReleases(versions, func(v *version.Version) bool {
+		return stableSet[v]
+	})
+	return stable, unstable, archived, nil
+}
+
+func (r Registry) versions() ([]*version.Version, map[*version.Version]bool, error) {
+	versions := make([]*version.Version, 0, len(r.releases))
+	stableSet := make(map[*version.Version]bool, len(r.releases))
+	for _, rel := range r.releases {
+		v, err := version.NewGoVersion(rel.Version, version.WithArtifacts(r.artifacts(rel.Files)))
+		if err != nil {
+			return nil, nil, err
+		}
+		stableSet[v] = rel.Stable
+		versions = append(versions, v)
+	}
+	return versions, stableSet, nil
+}
+
+// artifacts 将 JSON 中的文件列表转换为构件信息
+func (r Registry) artifacts(files []file) []version.ArtifactInfo {
+	artifacts := make([]version.ArtifactInfo, 0, len(files))
+	for _, f := range files {
+		kind, ok := kinds[strings.ToLower(f.Kind)]
+		if !ok {
+			kind = version.Kind(f.Kind)
+		}
+		artifacts = append(artifacts, version.ArtifactInfo{
+			FileName:  f.FileName,
+			URL:       r.url.JoinPath(f.FileName).String(),
+			Kind:      kind,
+			OS:        version.OS(f.OS),
+			Arch:      version.ARCH(f.Arch),
+			Size:      formatSize(f.Size),
+			Checksum:  f.SHA256,
+			Algorithm: string(utils.SHA256),
+		})
+	}
+	return artifacts
+}
+
+// formatSize 与官方下载页保持一致的大小展示，如 "66MB"
+func formatSize(size int64) string {
+	switch {
+	case size <= 0:
+		return ""
+	case size >= 1<<20:
+		return fmt.Sprintf("%dMB", size>>20)
+	default:
+		return fmt.Sprintf("%dKB", size>>10)
+	}
+}
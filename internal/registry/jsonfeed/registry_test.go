@@ -0,0 +1,98 @@
+package jsonfeed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/the-yex/gvm/internal/version"
+)
+
+const feed = `[
+  {"version": "go1.23rc1", "stable": false, "files": [
+    {"filename": "go1.23rc1.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.23rc1", "sha256": "aaa", "size": 70000000, "kind": "archive"}
+  ]},
+  {"version": "go1.22.5", "stable": true, "files": [
+    {"filename": "go1.22.5.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.22.5", "sha256": "bbb", "size": 69000000, "kind": "archive"},
+    {"filename": "go1.22.5.src.tar.gz", "os": "", "arch": "", "version": "go1.22.5", "sha256": "ccc", "size": 27000000, "kind": "source"}
+  ]},
+  {"version": "go1.22.4", "stable": true, "files": []},
+  {"version": "go1.21.12", "stable": true, "files": []},
+  {"version": "go1.20.14", "stable": true, "files": []},
+  {"version": "go1.22rc2", "stable": false, "files": []}
+]`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dl/" || r.URL.Query().Get("mode") != "json" || r.URL.Query().Get("include") != "all" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(feed))
+	}))
+}
+
+func TestRegistry(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	r, err := NewRegistry(srv.URL+"/dl/?mode=json", 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	stable, err := r.StableVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(stable); !slices.Equal(got, []string{"1.22.5", "1.21.12"}) {
+		t.Errorf("stable = %v", got)
+	}
+
+	unstable, _ := r.UnstableVersions()
+	if got := names(unstable); !slices.Equal(got, []string{"1.23.0-rc1"}) {
+		t.Errorf("unstable = %v", got)
+	}
+
+	archived, _ := r.ArchivedVersions()
+	if got := names(archived); !slices.Equal(got, []string{"1.22.4", "1.22.0-rc2", "1.20.14"}) {
+		t.Errorf("archived = %v", got)
+	}
+
+	all, _ := r.AllVersions()
+	if len(all) != 6 {
+		t.Fatalf("all versions = %d, want 6", len(all))
+	}
+
+	artifact := stable[0].Artifacts[0]
+	if artifact.URL != srv.URL+"/dl/go1.22.5.linux-amd64.tar.gz" {
+		t.Errorf("artifact url = %s", artifact.URL)
+	}
+	if artifact.Kind != "Archive" || artifact.OS != "linux" || artifact.Arch != "amd64" {
+		t.Errorf("artifact = %+v", artifact)
+	}
+	if artifact.Checksum != "bbb" || artifact.Algorithm != "SHA256" || artifact.Size != "65MB" {
+		t.Errorf("artifact checksum/size = %+v", artifact)
+	}
+}
+
+func TestRegistry_StatusError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := NewRegistry(srv.URL+"/dl/", time.Second); err == nil {
+		t.Fatal("expected error for non-200 response")
+	}
+}
+
+func names(versions []*version.Version) []string {
+	out := make([]string, 0, len(versions))
+	for _, v := range versions {
+		out = append(out, v.String())
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package localdir
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/the-yex/gvm/internal/registry/internal"
+	"github.com/the-yex/gvm/internal/utils"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Registry 读取本地目录（或 file:// URL）中的 Go 安装包及 .sha256 文件，
+// 用于无法访问外网的机器离线安装。
+type Registry struct {
+	dir   string
+	items []*internal.GoFileItem
+}
+
+// NewRegistry 扫描 mirrorUrl 指向的本地目录，mirrorUrl 可以是 file:// URL 或普通路径
+func NewRegistry(mirrorUrl string) (*Registry, error) {
+	dir, err := Dir(mirrorUrl)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	r := &Registry{dir: dir}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		r.items = append(r.items, &internal.GoFileItem{
+			FileName: entry.Name(),
+			URL:      FileURL(filepath.Join(dir, entry.Name())),
+			Size:     formatSize(info.Size()),
+		})
+	}
+	return r, nil
+}
+
+// Dir 将 file:// URL 或普通路径转换为本地目录的绝对路径
+func Dir(mirrorUrl string) (string, error) {
+	dir := mirrorUrl
+	if path, ok := utils.FilePath(mirrorUrl); ok {
+		dir = path
+	}
+	if strings.HasPrefix(dir, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, dir[1:])
+	}
+	return filepath.Abs(dir)
+}
+
+// FileURL 返回本地文件对应的 file:// URL
+func FileURL(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+func (r Registry) StableVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) UnstableVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) ArchivedVersions() (versions []*version.Version, err error) {
+	return r.AllVersions()
+}
+
+func (r Registry) AllVersions() (versions []*version.Version, err error) {
+	if len(r.items) == 0 {
+		return nil, nil
+	}
+	return internal.Convert2Versions(r.items)
+}
+
+func formatSize(size int64) string {
+	if size >= 1024*1024 {
+		return fmt.Sprintf("%.1f MB", float64(size)/1024/1024)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
@@ -0,0 +1,63 @@
+package localdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/utils"
+)
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go1.22.5.linux-amd64.tar.gz":        "archive",
+		"go1.22.5.linux-amd64.tar.gz.sha256": "abc123  go1.22.5.linux-amd64.tar.gz\n",
+		"go1.21.12.darwin-arm64.tar.gz":      "archive",
+		"README.txt":                         "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, mirrorUrl := range []string{dir, FileURL(dir)} {
+		r, err := NewRegistry(mirrorUrl)
+		if err != nil {
+			t.Fatalf("NewRegistry(%s): %v", mirrorUrl, err)
+		}
+		versions, err := r.AllVersions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 {
+			t.Fatalf("versions = %d, want 2", len(versions))
+		}
+	}
+
+	r, _ := NewRegistry(FileURL(dir))
+	versions, _ := r.AllVersions()
+	for _, v := range versions {
+		if v.String() != "1.22.5" {
+			continue
+		}
+		artifact := v.Artifacts[0]
+		path, ok := utils.FilePath(artifact.URL)
+		if !ok || path != filepath.Join(dir, "go1.22.5.linux-amd64.tar.gz") {
+			t.Errorf("artifact url = %s", artifact.URL)
+		}
+		checksum, err := utils.FetchChecksum(artifact.ChecksumURL)
+		if err != nil || checksum != "abc123" {
+			t.Errorf("checksum = %q, %v", checksum, err)
+		}
+		return
+	}
+	t.Fatal("go1.22.5 not found")
+}
+
+func TestNewRegistry_MissingDir(t *testing.T) {
+	if _, err := NewRegistry(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
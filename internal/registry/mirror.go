@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+)
+
+// Parser 表示镜像索引页的解析方式
+type Parser string
+
+const (
+	ParserOfficial   Parser = "official"   // go.dev/dl 风格的 HTML 下载页
+	ParserJSON       Parser = "json"       // go.dev/dl 的 ?mode=json 清单
+	ParserFancyIndex Parser = "fancyindex" // nginx fancyindex 目录页
+	ParserAutoIndex  Parser = "autoindex"  // nginx autoindex 目录页
+	ParserDirectory  Parser = "directory"  // 任意包含安装包链接的普通目录页
+	ParserLocal      Parser = "local"      // 本地目录或 file:// URL，用于离线安装
+)
+
+// Parsers 返回所有支持的解析方式
+func Parsers() []Parser {
+	return []Parser{ParserOfficial, ParserJSON, ParserFancyIndex, ParserAutoIndex, ParserDirectory, ParserLocal}
+}
+
+// Classified 报告该解析方式的索引是否自带 stable / unstable / archived 分类，目录页只有文件列表
+func (p Parser) Classified() bool {
+	return p == ParserOfficial || p == ParserJSON
+}
+
+// ParseParser 校验并返回解析方式
+func ParseParser(s string) (Parser, error) {
+	p := Parser(strings.ToLower(strings.TrimSpace(s)))
+	if slices.Contains(Parsers(), p) {
+		return p, nil
+	}
+	names := make([]string, 0, len(Parsers()))
+	for _, parser := range Parsers() {
+		names = append(names, string(parser))
+	}
+	return "", fmt.Errorf("invalid parser: %q, must be %s", s, strings.Join(names, " | "))
+}
+
+// Mirror 描述一个 Go 安装包镜像
+type Mirror struct {
+	Name   string `mapstructure:"name"`
+	URL    string `mapstructure:"url"`
+	Parser Parser `mapstructure:"parser"`
+}
+
+func (m Mirror) String() string {
+	if m.Name == "" {
+		return m.URL
+	}
+	return fmt.Sprintf("%s (%s)", m.Name, m.URL)
+}
+
+// DefaultMirrors 返回内置的镜像列表，配置文件中没有 mirrors 时使用
+func DefaultMirrors() []Mirror {
+	return []Mirror{
+		{Name: "official", URL: "https://go.dev/dl/", Parser: ParserOfficial},
+		{Name: "cn-official", URL: "https://golang.google.cn/dl/", Parser: ParserOfficial},
+		{Name: "official-json", URL: "https://go.dev/dl/?mode=json", Parser: ParserJSON},
+		{Name: "cn-official-json", URL: "https://golang.google.cn/dl/?mode=json", Parser: ParserJSON},
+		{Name: "aliyun", URL: "https://mirrors.aliyun.com/golang/", Parser: ParserFancyIndex},
+		{Name: "hust", URL: "https://mirrors.hust.edu.cn/golang/", Parser: ParserFancyIndex},
+		{Name: "nju", URL: "https://mirrors.nju.edu.cn/golang/", Parser: ParserFancyIndex},
+		{Name: "ustc", URL: "https://mirrors.ustc.edu.cn/golang/", Parser: ParserAutoIndex},
+	}
+}
+
+// ConfiguredMirrors 返回配置文件 mirrors 中的镜像，未配置时返回内置列表
+func ConfiguredMirrors() ([]Mirror, error) {
+	if !viper.IsSet(consts.CONFIG_MIRRORS) {
+		return DefaultMirrors(), nil
+	}
+	var mirrors []Mirror
+	if err := viper.UnmarshalKey(consts.CONFIG_MIRRORS, &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", consts.CONFIG_MIRRORS, err)
+	}
+	for i := range mirrors {
+		if mirrors[i].Parser == "" {
+			mirrors[i].Parser = ParserFancyIndex
+		}
+	}
+	return mirrors, nil
+}
+
+// LookupMirror 按名称或 URL 在已配置的镜像中查找
+func LookupMirror(nameOrURL string) (Mirror, error) {
+	mirrors, err := ConfiguredMirrors()
+	if err != nil {
+		return Mirror{}, err
+	}
+	for _, m := range mirrors {
+		if strings.EqualFold(m.Name, nameOrURL) {
+			return m, nil
+		}
+	}
+	for _, m := range mirrors {
+		if sameURL(m.URL, nameOrURL) {
+			return m, nil
+		}
+	}
+	supported := make([]string, 0, len(mirrors))
+	for _, m := range mirrors {
+		supported = append(supported, m.String())
+	}
+	return Mirror{}, fmt.Errorf(
+		"无效的镜像: %q\n已配置的镜像如下（可通过 gvm mirror add 添加）:\n  %s",
+		nameOrURL,
+		strings.Join(supported, "\n  "),
+	)
+}
+
+// AddMirror 添加或更新同名镜像并写回配置文件
+func AddMirror(m Mirror) error {
+	if m.Name == "" || m.URL == "" {
+		return fmt.Errorf("mirror name and url are required")
+	}
+	if _, err := ParseParser(string(m.Parser)); err != nil {
+		return err
+	}
+	mirrors, err := ConfiguredMirrors()
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(mirrors, func(e Mirror) bool { return strings.EqualFold(e.Name, m.Name) }); i >= 0 {
+		mirrors[i] = m
+	} else {
+		mirrors = append(mirrors, m)
+	}
+	return SaveMirrors(mirrors)
+}
+
+// RemoveMirror 删除指定名称的镜像并写回配置文件
+func RemoveMirror(name string) error {
+	mirrors, err := ConfiguredMirrors()
+	if err != nil {
+		return err
+	}
+	matchName := func(m Mirror) bool { return strings.EqualFold(m.Name, name) }
+	if !slices.ContainsFunc(mirrors, matchName) {
+		return fmt.Errorf("mirror %q not found", name)
+	}
+	remaining := slices.DeleteFunc(mirrors, matchName)
+	return SaveMirrors(remaining)
+}
+
+// SaveMirrors 将镜像列表写入配置文件
+func SaveMirrors(mirrors []Mirror) error {
+	viper.Set(consts.CONFIG_MIRRORS, MirrorsConfig(mirrors))
+	return viper.WriteConfig()
+}
+
+// MirrorsConfig 将镜像列表转换为可写入配置文件的结构
+func MirrorsConfig(mirrors []Mirror) []map[string]string {
+	values := make([]map[string]string, 0, len(mirrors))
+	for _, m := range mirrors {
+		values = append(values, map[string]string{
+			"name":   m.Name,
+			"url":    m.URL,
+			"parser": string(m.Parser),
+		})
+	}
+	return values
+}
+
+func sameURL(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func TestLookupMirror(t *testing.T) {
+	testutil.ConfigFile(t, "mirror: official\n")
+
+	m, err := LookupMirror("https://mirrors.ustc.edu.cn/golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "ustc" || m.Parser != ParserAutoIndex {
+		t.Errorf("lookup by url = %+v", m)
+	}
+
+	if _, err = LookupMirror("https://unknown.example.com/golang/"); err == nil {
+		t.Error("expected error for unknown mirror")
+	}
+}
+
+func TestAddRemoveMirror(t *testing.T) {
+	file := testutil.ConfigFile(t, "mirror: official\n")
+
+	corp := Mirror{Name: "corp", URL: "https://artifactory.example.com/golang/", Parser: ParserDirectory}
+	if err := AddMirror(corp); err != nil {
+		t.Fatal(err)
+	}
+	testutil.ReloadConfig(t, file)
+
+	mirrors, err := ConfiguredMirrors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mirrors) != len(DefaultMirrors())+1 {
+		t.Fatalf("mirrors = %d, want %d", len(mirrors), len(DefaultMirrors())+1)
+	}
+	if m, err := LookupMirror("CORP"); err != nil || m != corp {
+		t.Fatalf("lookup corp = %+v, %v", m, err)
+	}
+
+	if err = RemoveMirror("corp"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LookupMirror("corp"); err == nil {
+		t.Error("expected corp to be removed")
+	}
+	if err = RemoveMirror("corp"); err == nil {
+		t.Error("expected error removing unknown mirror")
+	}
+
+	if err = AddMirror(Mirror{Name: "bad", URL: "https://example.com/", Parser: "xml"}); err == nil {
+		t.Error("expected error for invalid parser")
+	}
+}
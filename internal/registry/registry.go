@@ -1,16 +1,18 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/viper"
 	"github.com/the-yex/gvm/internal/consts"
 	"github.com/the-yex/gvm/internal/registry/autoindex"
+	"github.com/the-yex/gvm/internal/registry/directory"
 	"github.com/the-yex/gvm/internal/registry/fancyindex"
+	"github.com/the-yex/gvm/internal/registry/internal"
+	"github.com/the-yex/gvm/internal/registry/jsonfeed"
+	"github.com/the-yex/gvm/internal/registry/localdir"
 	"github.com/the-yex/gvm/internal/registry/official"
 	"github.com/the-yex/gvm/internal/version"
-	"maps"
-	"slices"
-	"strings"
 	"time"
 )
 
@@ -21,35 +23,10 @@ import (
 * @Package:
  */
 
-// MirrorType 表示 Golang 下载镜像类型
-type MirrorType string
-
-const (
-	Official   MirrorType = "Official"   // https://go.dev/dl/
-	CNOfficial MirrorType = "CNOfficial" // https://golang.google.cn/dl/
-	Aliyun     MirrorType = "Aliyun"     // https://mirrors.aliyun.com/golang/
-	HUST       MirrorType = "HUST"       // https://mirrors.hust.edu.cn/golang/
-	NJU        MirrorType = "NJU"        // https://mirrors.nju.edu.cn/golang/
-	USTC       MirrorType = "USTC"       // https://mirrors.ustc.edu.cn/golang/
-)
-
-type Mirror struct {
-	Name string
-	URL  string
-}
-
-var Mirrors = map[string]MirrorType{
-	"https://go.dev/dl/":                  Official,
-	"https://golang.google.cn/dl/":        CNOfficial,
-	"https://mirrors.aliyun.com/golang/":  Aliyun,
-	"https://mirrors.hust.edu.cn/golang/": HUST,
-	"https://mirrors.nju.edu.cn/golang/":  NJU,
-	"https://mirrors.ustc.edu.cn/golang/": USTC,
-}
-
 type RegistryOption struct {
-	Timeout time.Duration
-	Mirror  string // Override config mirror
+	Timeout   time.Duration
+	Mirror    string   // Override config mirror, name or URL of a configured mirror
+	Fallbacks []string // 主镜像不可用时依次尝试的镜像，为空时读取 mirror_fallbacks 配置
 }
 
 type Registry interface {
@@ -63,28 +40,124 @@ type Registry interface {
 	AllVersions() (versions []*version.Version, err error)
 }
 
+// Kinds 记录每个版本（按 Original）的类型
+type Kinds map[string]consts.VersionKind
+
+// Classify 返回 rg 的所有版本及各版本的类型。parser 自带分类时使用 Registry 自己的结果，
+// 如官方下载页的分区与 JSON 清单的 stable 字段；目录页没有分类，按官方下载页的规则从版本号推断
+func Classify(rg Registry, parser Parser) ([]*version.Version, Kinds, error) {
+	var stable, unstable, archived []*version.Version
+	if parser.Classified() {
+		var err error
+		if stable, err = rg.StableVersions(); err != nil {
+			return nil, nil, err
+		}
+		if unstable, err = rg.UnstableVersions(); err != nil {
+			return nil, nil, err
+		}
+		if archived, err = rg.ArchivedVersions(); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		versions, err := rg.AllVersions()
+		if err != nil {
+			return nil, nil, err
+		}
+		stable, unstable, archived = internal.SplitReleases(versions, nil)
+	}
+	versions := make([]*version.Version, 0, len(stable)+len(unstable)+len(archived))
+	kinds := make(Kinds, cap(versions))
+	add := func(kind consts.VersionKind, list []*version.Version) {
+		for _, v := range list {
+			kinds[v.Original()] = kind
+			versions = append(versions, v)
+		}
+	}
+	add(consts.Stable, stable)
+	add(consts.Unstable, unstable)
+	add(consts.Archived, archived)
+	return versions, kinds, nil
+}
+
+// FilterKind 按 kinds 筛选出 kind 类型的版本，consts.All 原样返回
+func FilterKind(versions []*version.Version, kinds Kinds, kind consts.VersionKind) []*version.Version {
+	if kind == consts.All {
+		return versions
+	}
+	filtered := make([]*version.Version, 0, len(versions))
+	for _, v := range versions {
+		if kinds[v.Original()] == kind {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
 func NewRegistry(opts RegistryOption) (Registry, error) {
+	rg, _, err := Resolve(opts)
+	return rg, err
+}
+
+// Resolve 按 主镜像 → 备用镜像 的顺序创建 Registry，网络错误、超时或非 200 响应时
+// 自动尝试下一个镜像，返回实际提供数据的镜像
+func Resolve(opts RegistryOption) (Registry, Mirror, error) {
 	mirrorUrl := opts.Mirror
 	if mirrorUrl == "" {
 		mirrorUrl = viper.GetString(consts.CONFIG_MIRROR)
 	}
-	mirror, exist := Mirrors[mirrorUrl]
-	if !exist {
-		supported := slices.SortedStableFunc(maps.Keys(Mirrors), func(s string, s2 string) int {
-			return strings.Compare(s, s2)
-		})
-		return nil, fmt.Errorf(
-			"无效的配置 URL: %q\n支持的 URL 列表如下:\n  %s",
-			mirrorUrl,
-			strings.Join(supported, "\n  "),
-		)
+	fallbacks := opts.Fallbacks
+	if len(fallbacks) == 0 {
+		fallbacks = viper.GetStringSlice(consts.CONFIG_MIRROR_FALLBACKS)
 	}
-	switch mirror {
-	case Official, CNOfficial:
-		return official.NewRegistry(mirrorUrl, opts.Timeout)
-	case USTC:
-		return autoindex.NewRegistry(mirrorUrl, opts.Timeout)
+
+	var (
+		errs  []error
+		tried = make(map[string]bool, len(fallbacks)+1)
+	)
+	for i, name := range append([]string{mirrorUrl}, fallbacks...) {
+		if name == "" || name == consts.EMPTY_INFO {
+			continue
+		}
+		mirror, err := LookupMirror(name)
+		if err != nil {
+			if i == 0 && len(fallbacks) == 0 {
+				return nil, Mirror{}, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if tried[mirror.URL] {
+			continue
+		}
+		tried[mirror.URL] = true
+		rg, err := newRegistry(mirror, opts.Timeout)
+		if err == nil {
+			return rg, mirror, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", mirror.Name, err))
+	}
+	if len(errs) == 0 {
+		return nil, Mirror{}, fmt.Errorf("no mirror configured, set one with \"gvm config set mirror <name>\"")
+	}
+	return nil, Mirror{}, fmt.Errorf("all mirrors failed:\n%w", errors.Join(errs...))
+}
+
+// newRegistry 按镜像配置的解析方式创建对应的 Registry
+func newRegistry(mirror Mirror, timeout time.Duration) (Registry, error) {
+	switch mirror.Parser {
+	case ParserOfficial:
+		return official.NewRegistry(mirror.URL, timeout)
+	case ParserJSON:
+		return jsonfeed.NewRegistry(mirror.URL, timeout)
+	case ParserAutoIndex:
+		return autoindex.NewRegistry(mirror.URL, timeout)
+	case ParserFancyIndex:
+		return fancyindex.NewRegistry(mirror.URL, timeout)
+	case ParserDirectory:
+		return directory.NewRegistry(mirror.URL, timeout)
+	case ParserLocal:
+		return localdir.NewRegistry(mirror.URL)
 	default:
-		return fancyindex.NewRegistry(mirrorUrl, opts.Timeout)
+		return nil, fmt.Errorf("mirror %s: unsupported parser %q", mirror.Name, mirror.Parser)
 	}
 }
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/testutil"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+func TestResolve_Failover(t *testing.T) {
+	testutil.ConfigFile(t, "mirror: official\n")
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"version": "go1.22.5", "stable": true, "files": []}]`))
+	}))
+	defer up.Close()
+
+	viper.Set(consts.CONFIG_MIRRORS, MirrorsConfig([]Mirror{
+		{Name: "down", URL: down.URL + "/dl/", Parser: ParserJSON},
+		{Name: "up", URL: up.URL + "/dl/", Parser: ParserJSON},
+	}))
+
+	rg, served, err := Resolve(RegistryOption{Timeout: time.Second, Mirror: "down", Fallbacks: []string{"missing", "up"}})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if served.Name != "up" {
+		t.Errorf("served by %s, want up", served.Name)
+	}
+	versions, err := rg.AllVersions()
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("versions = %v, %v", versions, err)
+	}
+
+	if _, _, err = Resolve(RegistryOption{Timeout: time.Second, Mirror: "down", Fallbacks: []string{"down"}}); err == nil {
+		t.Error("expected error when every mirror fails")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	// JSON 清单把 go1.23.0 标记为不稳定，按版本号推断则是稳定版
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"version": "go1.23.0", "stable": false, "files": []},
+			{"version": "go1.22.5", "stable": true, "files": []},
+			{"version": "go1.21.12", "stable": true, "files": []},
+			{"version": "go1.20.14", "stable": true, "files": []}
+		]`))
+	}))
+	defer feed.Close()
+	rg, err := newRegistry(Mirror{Name: "feed", URL: feed.URL + "/dl/", Parser: ParserJSON}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, kinds, err := Classify(rg, ParserJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, versions, kinds, map[consts.VersionKind][]string{
+		consts.Stable:   {"1.22.5", "1.21.12"},
+		consts.Unstable: {"1.23.0"},
+		consts.Archived: {"1.20.14"},
+	})
+
+	// 本地目录没有分类，按版本号推断
+	dir := t.TempDir()
+	for _, name := range []string{"go1.23rc1", "go1.22.5", "go1.22.4", "go1.21.12"} {
+		if err = os.WriteFile(filepath.Join(dir, name+".linux-amd64.tar.gz"), []byte("archive"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rg, err = newRegistry(Mirror{Name: "local", URL: dir, Parser: ParserLocal}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if versions, kinds, err = Classify(rg, ParserLocal); err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, versions, kinds, map[consts.VersionKind][]string{
+		consts.Stable:   {"1.22.5", "1.21.12"},
+		consts.Unstable: {"1.23.0-rc1"},
+		consts.Archived: {"1.22.4"},
+	})
+	if got := FilterKind(versions, kinds, consts.All); len(got) != len(versions) {
+		t.Errorf("FilterKind(all) = %d versions, want %d", len(got), len(versions))
+	}
+}
+
+func assertKinds(t *testing.T, versions []*version.Version, kinds Kinds, want map[consts.VersionKind][]string) {
+	t.Helper()
+	for kind, names := range want {
+		var got []string
+		for _, v := range FilterKind(versions, kinds, kind) {
+			got = append(got, v.String())
+		}
+		if !slices.Equal(got, names) {
+			t.Errorf("%s = %v, want %v", kind, got, names)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// Shell 是 gvm init 支持的 shell
+type Shell string
+
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+)
+
+// Shells 返回所有支持的 shell
+func Shells() []Shell {
+	return []Shell{Bash, Zsh, Fish}
+}
+
+func Parse(s string) (Shell, error) {
+	for _, sh := range Shells() {
+		if strings.EqualFold(s, string(sh)) {
+			return sh, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported shell %q, must be bash | zsh | fish", s)
+}
+
+// ActiveRootEnv 记录当前 shell 会话中 gvm 设置的 GOROOT，切换目录时据此从 PATH 中移除旧版本
+const ActiveRootEnv = "GVM_GOROOT"
+
+// ActiveGopathEnv 记录当前 shell 会话中 gvm 按 pkgset 设置的 GOPATH，离开该 pkgset 时据此恢复用户原来的 GOPATH 与 GOBIN
+const ActiveGopathEnv = "GVM_GOPATH"
+
+// ActiveEnvKeysEnv 记录当前 shell 会话中 gvm 按配置 envs 设置的变量名（空格分隔），切换到其它版本时据此还原
+const ActiveEnvKeysEnv = "GVM_ENV_KEYS"
+
+// PrevEnvPrefix 加上变量名保存 gvm 设置该变量之前用户自己的值，设置前不存在该变量时不保存
+const PrevEnvPrefix = "__GVM_PREV_"
+
+// Quote 按 shell 的语法对值加单引号
+func (sh Shell) Quote(s string) string {
+	if sh == Fish {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Export 返回设置环境变量的语句
+func (sh Shell) Export(name, value string) string {
+	if sh == Fish {
+		return fmt.Sprintf("set -gx %s %s;", name, sh.Quote(value))
+	}
+	return fmt.Sprintf("export %s=%s;", name, sh.Quote(value))
+}
+
+// Unset 返回删除环境变量的语句
+func (sh Shell) Unset(name string) string {
+	if sh == Fish {
+		return fmt.Sprintf("set -e %s;", name)
+	}
+	return fmt.Sprintf("unset %s;", name)
+}
+
+// ExportPath 返回设置 PATH 的语句，fish 中 PATH 是列表
+func (sh Shell) ExportPath(entries []string) string {
+	if sh != Fish {
+		return sh.Export("PATH", strings.Join(entries, string(os.PathListSeparator)))
+	}
+	quoted := make([]string, len(entries))
+	for i, entry := range entries {
+		quoted[i] = sh.Quote(entry)
+	}
+	return "set -gx PATH " + strings.Join(quoted, " ") + ";"
+}
+
+// RewritePath 从 PATH 中移除 stale 目录下的 bin，再把 goRoot/bin 放到最前面
+func RewritePath(pathEnv, goRoot string, stale ...string) []string {
+	remove := map[string]bool{}
+	for _, root := range append(stale, goRoot) {
+		if root != "" {
+			remove[filepath.Clean(filepath.Join(root, "bin"))] = true
+		}
+	}
+	entries := []string{filepath.Join(goRoot, "bin")}
+	for _, entry := range filepath.SplitList(pathEnv) {
+		if entry == "" || remove[filepath.Clean(entry)] {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// WithGopath 将 gopath/bin 放在 RewritePath 返回的 goRoot/bin 之后，gopath 为空时原样返回
+func WithGopath(entries []string, gopath string) []string {
+	if gopath == "" {
+		return entries
+	}
+	return slices.Insert(entries, min(1, len(entries)), filepath.Join(gopath, "bin"))
+}
+
+// ProfileScript 返回在当前 shell 会话中应用配置 envs 的代码，env 为 KEY=VALUE 列表，lookup 读取当前会话的环境变量。
+// 首次设置某个变量时把用户原来的值保存到 PrevEnvPrefix+KEY，离开设置了该变量的版本时恢复原值，原来没有该变量时删除。
+func ProfileScript(sh Shell, env []string, lookup func(string) (string, bool)) []string {
+	activeKeys, _ := lookup(ActiveEnvKeysEnv)
+	previous := strings.Fields(activeKeys)
+	keys := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, key)
+	}
+
+	lines := applyEnv(sh, previous, env, lookup)
+	if len(keys) > 0 {
+		lines = append(lines, sh.Export(ActiveEnvKeysEnv, strings.Join(keys, " ")))
+	} else if len(previous) > 0 {
+		lines = append(lines, sh.Unset(ActiveEnvKeysEnv))
+	}
+	return lines
+}
+
+// PkgsetScript 返回在当前 shell 会话中使用 pkgset 的代码：gopath 不为空时设置 GOPATH 与 GOBIN，
+// 与 ProfileScript 一样保存用户原来的值，离开 pkgset 时恢复
+func PkgsetScript(sh Shell, gopath string, lookup func(string) (string, bool)) []string {
+	var previous, env []string
+	if active, _ := lookup(ActiveGopathEnv); active != "" {
+		previous = []string{"GOPATH", "GOBIN"}
+	}
+	if gopath != "" {
+		env = []string{"GOPATH=" + gopath, "GOBIN=" + filepath.Join(gopath, "bin")}
+	}
+
+	lines := applyEnv(sh, previous, env, lookup)
+	if gopath != "" {
+		lines = append(lines, sh.Export(ActiveGopathEnv, gopath))
+	} else if len(previous) > 0 {
+		lines = append(lines, sh.Unset(ActiveGopathEnv))
+	}
+	return lines
+}
+
+// applyEnv 返回把 env 应用到会话的代码，previous 为上一次由 gvm 设置的变量名：
+// 不再设置的变量恢复为 PrevEnvPrefix+KEY 中保存的值，新设置的变量先保存用户原来的值
+func applyEnv(sh Shell, previous, env []string, lookup func(string) (string, bool)) []string {
+	keys := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, key)
+	}
+
+	var lines []string
+	for _, key := range previous {
+		if slices.Contains(keys, key) {
+			continue
+		}
+		if value, ok := lookup(PrevEnvPrefix + key); ok {
+			lines = append(lines, sh.Export(key, value), sh.Unset(PrevEnvPrefix+key))
+		} else {
+			lines = append(lines, sh.Unset(key))
+		}
+	}
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if !slices.Contains(previous, key) {
+			if old, ok := lookup(key); ok {
+				lines = append(lines, sh.Export(PrevEnvPrefix+key, old))
+			}
+		}
+		lines = append(lines, sh.Export(key, value))
+	}
+	return lines
+}
+
+// Getenv 返回环境变量列表中 key 的值。与 exec.Cmd 对重复变量的处理一致，有多个时取最后一个
+func Getenv(environ []string, key string) string {
+	for i := len(environ) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(environ[i], key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// WithEnv 返回设置了 key=value 的环境变量列表，已有的同名变量全部移除，不修改 environ
+func WithEnv(environ []string, key, value string) []string {
+	env := make([]string, 0, len(environ)+1)
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, key+"=") {
+			env = append(env, kv)
+		}
+	}
+	return append(env, key+"="+value)
+}
+
+// LookPath 在给定的 PATH（而不是当前进程的 PATH）中查找可执行文件
+func LookPath(name, pathEnv string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/') {
+		return exec.LookPath(name)
+	}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		if path, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return path, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// ExitCode 将 exec.Cmd.Wait 的结果转换为 shell 风格的退出码：被信号终止时为 128+信号值。
+// 只有命令无法运行时才返回 error。
+func ExitCode(err error) (int, error) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// SplitExec 将 gvm exec 的参数拆分为版本与命令，dash 为 "--" 之前的参数个数（cobra 的 ArgsLenAtDash，没有 "--" 时为 -1）。
+// 没有 "--" 时第一个参数是版本；"--" 之前没有参数时返回空版本，由项目锁定的版本或全局版本决定。
+func SplitExec(args []string, dash int) (versionName string, command []string, err error) {
+	switch {
+	case dash > 1:
+		return "", nil, fmt.Errorf("expected a single version before \"--\", got %q", args[:dash])
+	case dash == 0:
+		if len(args) == 0 {
+			return "", nil, errors.New("missing command after \"--\"")
+		}
+		return "", args, nil
+	case len(args) < 2:
+		return "", nil, errors.New("expected a version and a command, e.g. \"gvm exec 1.22 -- go test ./...\"")
+	default:
+		return args[0], args[1:], nil
+	}
+}
+
+// InitScript 返回 gvm init 输出的 shell 代码：把全局版本加入 PATH，并在切换目录时执行 gvm hook
+func InitScript(sh Shell, exe, goRoot string) string {
+	gvm := sh.Quote(exe)
+	bin := sh.Quote(filepath.Join(goRoot, "bin"))
+	switch sh {
+	case Fish:
+		return fmt.Sprintf(`# gvm shell integration
+set -gx GOROOT %[3]s
+contains -- %[2]s $PATH; or set -gx PATH %[2]s $PATH
+
+function _gvm_hook --on-variable PWD
+    %[1]s hook fish | source
+end
+_gvm_hook
+`, gvm, bin, sh.Quote(goRoot))
+	case Zsh:
+		return fmt.Sprintf(`# gvm shell integration
+export GOROOT=%[3]s
+case ":$PATH:" in
+  *:%[2]s:*) ;;
+  *) export PATH=%[2]s:"$PATH" ;;
+esac
+
+_gvm_hook() {
+  eval "$(%[1]s hook zsh)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _gvm_hook
+_gvm_hook
+`, gvm, bin, sh.Quote(goRoot))
+	default:
+		return fmt.Sprintf(`# gvm shell integration
+export GOROOT=%[3]s
+case ":$PATH:" in
+  *:%[2]s:*) ;;
+  *) export PATH=%[2]s:"$PATH" ;;
+esac
+
+_gvm_hook() {
+  if [ "${_GVM_LAST_PWD:-}" != "$PWD" ]; then
+    _GVM_LAST_PWD="$PWD"
+    eval "$(%[1]s hook bash)"
+  fi
+}
+case ";${PROMPT_COMMAND:-};" in
+  *";_gvm_hook;"*) ;;
+  *) PROMPT_COMMAND="_gvm_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+_gvm_hook
+`, gvm, bin, sh.Quote(goRoot))
+	}
+}
@@ -0,0 +1,214 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRewritePath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	global := filepath.Join("/home/u/.gvm", "go")
+	previous := filepath.Join("/home/u/.gvm/sdk", "go1.21.0")
+	next := filepath.Join("/home/u/.gvm/sdk", "go1.22.0")
+	pathEnv := strings.Join([]string{
+		filepath.Join(previous, "bin"), "/usr/bin", filepath.Join(global, "bin"), "", "/bin",
+	}, sep)
+
+	got := RewritePath(pathEnv, next, previous, global)
+	want := []string{filepath.Join(next, "bin"), "/usr/bin", "/bin"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RewritePath = %v, want %v", got, want)
+	}
+
+	// 切回全局版本时不会重复添加
+	got = RewritePath(strings.Join(got, sep), global, next, global)
+	want = []string{filepath.Join(global, "bin"), "/usr/bin", "/bin"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RewritePath = %v, want %v", got, want)
+	}
+}
+
+func TestWithGopath(t *testing.T) {
+	gopath := filepath.Join("/home/u/.gvm/pkgsets/go1.22.0", "tools")
+	previous := filepath.Join("/home/u/.gvm/pkgsets/go1.21.0", "default")
+	next := filepath.Join("/home/u/.gvm/sdk", "go1.22.0")
+	pathEnv := strings.Join([]string{filepath.Join(previous, "bin"), "/usr/bin"}, string(os.PathListSeparator))
+
+	got := WithGopath(RewritePath(pathEnv, next, previous, gopath), gopath)
+	want := []string{filepath.Join(next, "bin"), filepath.Join(gopath, "bin"), "/usr/bin"}
+	if !slices.Equal(got, want) {
+		t.Errorf("WithGopath = %v, want %v", got, want)
+	}
+	if got = WithGopath(want[:1], ""); !slices.Equal(got, want[:1]) {
+		t.Errorf("WithGopath without gopath = %v", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		sh   Shell
+		want string
+	}{
+		{Bash, `export GOROOT='/opt/it'\''s go';`},
+		{Zsh, `export GOROOT='/opt/it'\''s go';`},
+		{Fish, `set -gx GOROOT '/opt/it\'s go';`},
+	}
+	for _, tt := range tests {
+		if got := tt.sh.Export("GOROOT", "/opt/it's go"); got != tt.want {
+			t.Errorf("%s: Export = %s, want %s", tt.sh, got, tt.want)
+		}
+	}
+	if got := Fish.ExportPath([]string{"/a b", "/c"}); got != `set -gx PATH '/a b' '/c';` {
+		t.Errorf("fish ExportPath = %s", got)
+	}
+	if got := Fish.Unset("GOPATH"); got != "set -e GOPATH;" {
+		t.Errorf("fish Unset = %s", got)
+	}
+	if got := Bash.Unset("GOPATH"); got != "unset GOPATH;" {
+		t.Errorf("bash Unset = %s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"bash", "ZSH", "fish"} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+		}
+	}
+	if _, err := Parse("powershell"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestInitScript(t *testing.T) {
+	for _, sh := range Shells() {
+		script := InitScript(sh, "/usr/local/bin/gvm", "/home/u/.gvm/go")
+		if !strings.Contains(script, "'/usr/local/bin/gvm' hook "+string(sh)) {
+			t.Errorf("%s script does not call the hook:\n%s", sh, script)
+		}
+	}
+}
+
+func TestSplitExec(t *testing.T) {
+	tests := []struct {
+		args    []string
+		dash    int
+		version string
+		command []string
+		wantErr bool
+	}{
+		{args: []string{"1.22", "go", "test", "./..."}, dash: 1, version: "1.22", command: []string{"go", "test", "./..."}},
+		{args: []string{"1.22", "go", "version"}, dash: -1, version: "1.22", command: []string{"go", "version"}},
+		// gvm exec -- go test ./...：go 是命令而不是版本
+		{args: []string{"go", "test", "./..."}, dash: 0, command: []string{"go", "test", "./..."}},
+		{args: []string{"1.22", "1.21", "go"}, dash: 2, wantErr: true},
+		{args: []string{}, dash: 0, wantErr: true},
+		{args: []string{"1.22"}, dash: 1, wantErr: true},
+		{args: []string{"1.22"}, dash: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		version, command, err := SplitExec(tt.args, tt.dash)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SplitExec(%q, %d) should fail", tt.args, tt.dash)
+			}
+			continue
+		}
+		if err != nil || version != tt.version || !slices.Equal(command, tt.command) {
+			t.Errorf("SplitExec(%q, %d) = %q, %q, %v, want %q, %q", tt.args, tt.dash, version, command, err, tt.version, tt.command)
+		}
+	}
+}
+
+func TestEnv(t *testing.T) {
+	environ := []string{"GOCACHE=/a", "PATH=/bin", "GOCACHE=/b"}
+	if got := Getenv(environ, "GOCACHE"); got != "/b" {
+		t.Errorf("Getenv = %s, want the last value", got)
+	}
+	if got := Getenv(environ, "GO"); got != "" {
+		t.Errorf("Getenv matched a prefix: %s", got)
+	}
+	env := WithEnv(environ, "GOCACHE", "/c")
+	if !slices.Equal(env, []string{"PATH=/bin", "GOCACHE=/c"}) {
+		t.Errorf("WithEnv = %q", env)
+	}
+	if environ[0] != "GOCACHE=/a" {
+		t.Error("WithEnv modified its input")
+	}
+}
+
+// applyScript 按 bash 代码更新模拟的会话环境变量
+func applyScript(t *testing.T, session map[string]string, lines []string) {
+	t.Helper()
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, ";")
+		if name, ok := strings.CutPrefix(line, "unset "); ok {
+			delete(session, name)
+			continue
+		}
+		kv, ok := strings.CutPrefix(line, "export ")
+		if !ok {
+			t.Fatalf("unexpected line %q", line)
+		}
+		name, value, _ := strings.Cut(kv, "=")
+		session[name] = strings.Trim(value, "'")
+	}
+}
+
+func TestProfileScript(t *testing.T) {
+	session := map[string]string{"GOFLAGS": "-mod=vendor"}
+	lookup := func(key string) (string, bool) {
+		v, ok := session[key]
+		return v, ok
+	}
+
+	// 进入设置了 GOFLAGS 与 GOEXPERIMENT 的版本
+	applyScript(t, session, ProfileScript(Bash, []string{"GOFLAGS=-mod=mod", "GOEXPERIMENT=rangefunc"}, lookup))
+	if session["GOFLAGS"] != "-mod=mod" || session["GOEXPERIMENT"] != "rangefunc" {
+		t.Fatalf("profile not applied: %v", session)
+	}
+	// 切换到只设置 GOFLAGS 的版本：不能把 gvm 设置的值当作用户的值保存
+	applyScript(t, session, ProfileScript(Bash, []string{"GOFLAGS=-trimpath"}, lookup))
+	if session["GOFLAGS"] != "-trimpath" || session[PrevEnvPrefix+"GOFLAGS"] != "-mod=vendor" {
+		t.Fatalf("switching profiles: %v", session)
+	}
+	if _, ok := session["GOEXPERIMENT"]; ok {
+		t.Errorf("GOEXPERIMENT should be removed: %v", session)
+	}
+	// 离开后恢复用户原来的值
+	applyScript(t, session, ProfileScript(Bash, nil, lookup))
+	want := map[string]string{"GOFLAGS": "-mod=vendor"}
+	if len(session) != len(want) || session["GOFLAGS"] != want["GOFLAGS"] {
+		t.Errorf("session after leaving = %v, want %v", session, want)
+	}
+}
+
+func TestPkgsetScript(t *testing.T) {
+	session := map[string]string{"GOPATH": "/home/u/go"}
+	lookup := func(key string) (string, bool) {
+		v, ok := session[key]
+		return v, ok
+	}
+
+	// 进入使用 pkgset 的版本
+	applyScript(t, session, PkgsetScript(Bash, "/home/u/.gvm/pkgsets/go1.22.0/tools", lookup))
+	if session["GOPATH"] != "/home/u/.gvm/pkgsets/go1.22.0/tools" || session["GOBIN"] != filepath.Join("/home/u/.gvm/pkgsets/go1.22.0/tools", "bin") {
+		t.Fatalf("pkgset not applied: %v", session)
+	}
+	// 切换到另一个 pkgset 时保留最初保存的值
+	applyScript(t, session, PkgsetScript(Bash, "/home/u/.gvm/pkgsets/go1.21.0/default", lookup))
+	if session[PrevEnvPrefix+"GOPATH"] != "/home/u/go" {
+		t.Fatalf("saved GOPATH lost: %v", session)
+	}
+	// 离开 pkgset 后恢复用户自己的 GOPATH，原来没有的 GOBIN 被删除
+	applyScript(t, session, PkgsetScript(Bash, "", lookup))
+	if len(session) != 1 || session["GOPATH"] != "/home/u/go" {
+		t.Errorf("session after leaving = %v, want only GOPATH=/home/u/go", session)
+	}
+	if lines := PkgsetScript(Bash, "", lookup); len(lines) != 0 {
+		t.Errorf("no pkgset before or after should print nothing, got %q", lines)
+	}
+}
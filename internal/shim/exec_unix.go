@@ -0,0 +1,10 @@
+//go:build !windows
+
+package shim
+
+import "syscall"
+
+// Exec 用 path 替换当前进程，成功时不会返回
+func Exec(path string, argv []string, env []string) error {
+	return syscall.Exec(path, argv, env)
+}
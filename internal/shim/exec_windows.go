@@ -0,0 +1,26 @@
+//go:build windows
+
+package shim
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// Exec 在 Windows 上无法替换当前进程，改为运行子进程并以其退出码退出，成功时不会返回
+func Exec(path string, argv []string, env []string) error {
+	cmd := exec.Command(path, argv[1:]...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
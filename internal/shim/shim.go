@@ -0,0 +1,91 @@
+package shim
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Names 是会在 shims 目录中创建的命令
+var Names = []string{"go", "gofmt"}
+
+// Name 根据 argv[0] 判断 gvm 是否以 shim 的身份被调用，返回对应的命令名
+func Name(argv0 string) (string, bool) {
+	base := filepath.Base(argv0)
+	if runtime.GOOS == "windows" {
+		base = strings.TrimSuffix(strings.ToLower(base), ".exe")
+	}
+	for _, name := range Names {
+		if base == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Executable 返回命令在 bin 目录中的文件名
+func Executable(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// Install 在 dir 中创建指向 gvm 可执行文件 exe 的 shim，已存在的 shim 会被替换。
+// Windows 下创建符号链接需要特权，因此复制可执行文件，升级 gvm 后需要重新执行。
+func Install(dir, exe string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var created []string
+	for _, name := range Names {
+		target := filepath.Join(dir, Executable(name))
+		tmp := target + ".tmp"
+		os.Remove(tmp)
+		var err error
+		if runtime.GOOS == "windows" {
+			err = copyFile(exe, tmp)
+		} else {
+			err = os.Symlink(exe, tmp)
+		}
+		if err == nil {
+			err = os.Rename(tmp, target)
+		}
+		if err != nil {
+			os.Remove(tmp)
+			return created, fmt.Errorf("failed to create shim %s: %w", target, err)
+		}
+		created = append(created, target)
+	}
+	return created, nil
+}
+
+// Remove 删除 dir 中的 shim
+func Remove(dir string) error {
+	for _, name := range Names {
+		if err := os.Remove(filepath.Join(dir, Executable(name))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,58 @@
+package shim
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	tests := map[string]bool{
+		"/home/u/.gvm/shims/go":    true,
+		"gofmt":                    true,
+		"/usr/local/bin/gvm":       false,
+		"/home/u/.gvm/shims/gopls": false,
+	}
+	for argv0, want := range tests {
+		if _, ok := Name(argv0); ok != want {
+			t.Errorf("Name(%q) = %v, want %v", argv0, ok, want)
+		}
+	}
+}
+
+func TestInstallRemove(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shims")
+	exe := filepath.Join(t.TempDir(), "gvm")
+	if err := os.WriteFile(exe, []byte("gvm"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 重复执行会替换已有的 shim
+	for range 2 {
+		created, err := Install(dir, exe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created) != len(Names) {
+			t.Fatalf("created %v", created)
+		}
+	}
+	for _, name := range Names {
+		data, err := os.ReadFile(filepath.Join(dir, Executable(name)))
+		if err != nil || string(data) != "gvm" {
+			t.Errorf("shim %s does not run gvm: %q, %v", name, data, err)
+		}
+		if runtime.GOOS != "windows" {
+			if target, _ := os.Readlink(filepath.Join(dir, name)); target != exe {
+				t.Errorf("shim %s -> %s, want %s", name, target, exe)
+			}
+		}
+	}
+	if err := Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("shims left after Remove: %v", entries)
+	}
+}
@@ -0,0 +1,64 @@
+// Package testutil 提供各包测试共用的配置与 GVM_HOME 隔离
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+)
+
+// Config 以 yaml 内容 config 作为测试期间的配置，测试结束后清空
+func Config(t testing.TB, config string) {
+	t.Helper()
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(viper.Reset)
+}
+
+// ConfigFile 将 config 写入临时配置文件并加载，返回文件路径，用于测试会写回配置的操作
+func ConfigFile(t testing.TB, config string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ReloadConfig(t, file)
+	t.Cleanup(viper.Reset)
+	return file
+}
+
+// ReloadConfig 重新读取配置文件，用于检查写回的内容
+func ReloadConfig(t testing.TB, file string) {
+	t.Helper()
+	viper.Reset()
+	viper.SetConfigFile(file)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GvmHome 将 GVM_HOME 及其下的各目录指向临时目录，测试结束后恢复，返回新的 GVM_HOME
+func GvmHome(t testing.TB) string {
+	t.Helper()
+	home := t.TempDir()
+	old := []string{consts.GVM_HOME, consts.GO_ROOT, consts.VERSION_DIR, consts.CACHE_DIR, consts.SHIMS_DIR, consts.PKGSET_DIR, consts.GOPATH_LINK}
+	consts.GVM_HOME = home
+	consts.GO_ROOT = filepath.Join(home, "go")
+	consts.VERSION_DIR = filepath.Join(home, "sdk")
+	consts.CACHE_DIR = filepath.Join(home, "cache")
+	consts.SHIMS_DIR = filepath.Join(home, "shims")
+	consts.PKGSET_DIR = filepath.Join(home, "pkgsets")
+	consts.GOPATH_LINK = filepath.Join(home, "gopath")
+	t.Cleanup(func() {
+		consts.GVM_HOME, consts.GO_ROOT, consts.VERSION_DIR, consts.CACHE_DIR = old[0], old[1], old[2], old[3]
+		consts.SHIMS_DIR, consts.PKGSET_DIR, consts.GOPATH_LINK = old[4], old[5], old[6]
+	})
+	return home
+}
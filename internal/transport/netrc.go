@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// netrcPath 返回 netrc 文件路径，优先使用 $NETRC
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name)
+}
+
+// netrcLookup 在 netrc 文件中查找 host 的登录信息，allowDefault 为 true 时没有精确匹配会使用 default 条目
+func netrcLookup(host string, allowDefault bool) (login, password string, found bool) {
+	path := netrcPath()
+	if path == "" {
+		return "", "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+	for _, entry := range parseNetrc(string(data)) {
+		if strings.EqualFold(entry.machine, host) {
+			return entry.login, entry.password, true
+		}
+	}
+	if allowDefault {
+		for _, entry := range parseNetrc(string(data)) {
+			if entry.machine == "" {
+				return entry.login, entry.password, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+type netrcEntry struct {
+	machine  string // 为空表示 default 条目
+	login    string
+	password string
+}
+
+func parseNetrc(data string) []netrcEntry {
+	var (
+		entries []netrcEntry
+		current *netrcEntry
+	)
+	fields := strings.Fields(data)
+	next := func(i *int) string {
+		if *i+1 < len(fields) {
+			*i++
+			return fields[*i]
+		}
+		return ""
+	}
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			entries = append(entries, netrcEntry{machine: next(&i)})
+			current = &entries[len(entries)-1]
+		case "default":
+			entries = append(entries, netrcEntry{})
+			current = &entries[len(entries)-1]
+		case "login":
+			if login := next(&i); current != nil {
+				current.login = login
+			}
+		case "password":
+			if password := next(&i); current != nil {
+				current.password = password
+			}
+		case "account":
+			next(&i)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,167 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/consts"
+)
+
+// DefaultUserAgent 部分镜像会拒绝非浏览器的请求，默认沿用浏览器 UA，可通过 user_agent 配置覆盖
+const DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36"
+
+// AuthType 镜像主机的认证方式
+type AuthType string
+
+const (
+	Basic  AuthType = "basic"
+	Bearer AuthType = "bearer"
+	Netrc  AuthType = "netrc"
+)
+
+// Auth 是 auth 配置段中单个主机的认证信息。
+// 配置段使用列表而不是以主机名为 key 的 map，避免 viper 把主机名中的 "." 当作层级分隔符。
+type Auth struct {
+	Host     string   `mapstructure:"host"` // 主机名，可带端口
+	Type     AuthType `mapstructure:"type"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	Token    string   `mapstructure:"token"`
+}
+
+var (
+	once      sync.Once
+	shared    http.RoundTripper
+	sharedErr error
+)
+
+// Client 返回使用共享 Transport 的 http.Client，timeout 为 0 表示不超时
+func Client(timeout time.Duration) (*http.Client, error) {
+	once.Do(func() {
+		shared, sharedErr = newTransport()
+	})
+	if sharedErr != nil {
+		return nil, sharedErr
+	}
+	return &http.Client{Timeout: timeout, Transport: shared}, nil
+}
+
+// NewRequest 创建 GET 等请求，并附加 User-Agent 与镜像主机对应的认证信息
+func NewRequest(method, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	userAgent := viper.GetString(consts.CONFIG_USER_AGENT)
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if err = authorize(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Do 使用共享 Transport 发送请求
+func Do(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	client, err := Client(timeout)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// Get 等价于 NewRequest + Do
+func Get(rawURL string, timeout time.Duration) (*http.Response, error) {
+	req, err := NewRequest(http.MethodGet, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return Do(req, timeout)
+}
+
+func newTransport() (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy := viper.GetString(consts.CONFIG_PROXY); proxy != "" && proxy != consts.EMPTY_INFO {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s config %q: %w", consts.CONFIG_PROXY, proxy, err)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if caFile := viper.GetString(consts.CONFIG_CA_FILE); caFile != "" && caFile != consts.EMPTY_INFO {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return t, nil
+}
+
+// loadCertPool 在系统根证书的基础上追加自定义 CA
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %q: %w", consts.CONFIG_CA_FILE, caFile, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s %q", consts.CONFIG_CA_FILE, caFile)
+	}
+	return pool, nil
+}
+
+// authorize 按请求主机查找 auth 配置，未配置时尝试 ~/.netrc
+func authorize(req *http.Request) error {
+	host := req.URL.Hostname()
+	var auths []Auth
+	if err := viper.UnmarshalKey(consts.CONFIG_AUTH, &auths); err != nil {
+		return fmt.Errorf("invalid %s config: %w", consts.CONFIG_AUTH, err)
+	}
+	auth, ok := lookupAuth(auths, req.URL)
+	if !ok {
+		auth = Auth{Type: Netrc}
+	}
+	switch AuthType(strings.ToLower(string(auth.Type))) {
+	case Basic:
+		req.SetBasicAuth(os.ExpandEnv(auth.Username), os.ExpandEnv(auth.Password))
+	case Bearer:
+		req.Header.Set("Authorization", "Bearer "+os.ExpandEnv(auth.Token))
+	case Netrc:
+		if login, password, found := netrcLookup(host, ok); found {
+			req.SetBasicAuth(login, password)
+		} else if ok {
+			return fmt.Errorf("no netrc entry found for %s", host)
+		}
+	default:
+		return fmt.Errorf("invalid auth type %q for %s, must be basic | bearer | netrc", auth.Type, host)
+	}
+	return nil
+}
+
+// lookupAuth 依次按 host:port 与 host 匹配认证配置
+func lookupAuth(auths []Auth, u *url.URL) (Auth, bool) {
+	for _, host := range []string{u.Host, u.Hostname()} {
+		for _, auth := range auths {
+			if strings.EqualFold(auth.Host, host) {
+				return auth, true
+			}
+		}
+	}
+	return Auth{}, false
+}
@@ -0,0 +1,142 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/testutil"
+)
+
+func useConfig(t *testing.T, config string) {
+	t.Helper()
+	testutil.Config(t, config)
+	once = sync.Once{}
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "netrc"))
+	t.Cleanup(func() { once = sync.Once{} })
+}
+
+func echoAuth(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-User-Agent", r.UserAgent())
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func authHeader(t *testing.T, rawURL string) (string, string) {
+	t.Helper()
+	resp, err := Get(rawURL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), resp.Header.Get("X-User-Agent")
+}
+
+func TestAuthorize(t *testing.T) {
+	srv := echoAuth(t)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	t.Run("basic", func(t *testing.T) {
+		useConfig(t, "auth:\n  - host: "+host+"\n    type: basic\n    username: ci\n    password: $GVM_TEST_PASSWORD\n")
+		t.Setenv("GVM_TEST_PASSWORD", "secret")
+		got, ua := authHeader(t, srv.URL)
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.SetBasicAuth("ci", "secret")
+		if got != req.Header.Get("Authorization") {
+			t.Errorf("Authorization = %q", got)
+		}
+		if ua != DefaultUserAgent {
+			t.Errorf("User-Agent = %q", ua)
+		}
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		useConfig(t, "user_agent: gvm-test\nauth:\n  - host: 127.0.0.1\n    type: bearer\n    token: abc\n")
+		got, ua := authHeader(t, srv.URL)
+		if got != "Bearer abc" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if ua != "gvm-test" {
+			t.Errorf("User-Agent = %q", ua)
+		}
+	})
+
+	t.Run("netrc", func(t *testing.T) {
+		useConfig(t, "mirror: official\n")
+		netrc := "machine 127.0.0.1 login alice password pw\ndefault login anon password none\n"
+		if err := os.WriteFile(os.Getenv("NETRC"), []byte(netrc), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := authHeader(t, srv.URL)
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.SetBasicAuth("alice", "pw")
+		if got != req.Header.Get("Authorization") {
+			t.Errorf("Authorization = %q", got)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		useConfig(t, "mirror: official\n")
+		if got, _ := authHeader(t, srv.URL); got != "" {
+			t.Errorf("Authorization = %q, want empty", got)
+		}
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		useConfig(t, "auth:\n  - host: 127.0.0.1\n    type: digest\n")
+		if _, err := NewRequest(http.MethodGet, srv.URL); err == nil {
+			t.Error("expected error for unsupported auth type")
+		}
+	})
+}
+
+func TestParseNetrc(t *testing.T) {
+	entries := parseNetrc(`
+machine example.com
+  login u1
+  password p1
+machine other.com login u2 account acc password p2
+default login anon password none
+`)
+	want := []netrcEntry{
+		{machine: "example.com", login: "u1", password: "p1"},
+		{machine: "other.com", login: "u2", password: "p2"},
+		{login: "anon", password: "none"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestClient_InvalidCAFile(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(bad, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, caFile := range []string{bad, filepath.Join(dir, "missing.pem")} {
+		useConfig(t, consts.CONFIG_CA_FILE+": "+caFile+"\n")
+		if _, err := Client(0); err == nil {
+			t.Errorf("expected error for ca_file %s", caFile)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/the-yex/gvm/internal/consts"
 	"github.com/the-yex/gvm/internal/core"
+	"github.com/the-yex/gvm/internal/lock"
 	progress2 "github.com/the-yex/gvm/internal/tui/progress"
 	"github.com/the-yex/gvm/internal/version"
 	"strings"
@@ -77,9 +78,22 @@ type Model struct {
 }
 
 type FooterInfo struct {
-	Mirror  string
-	Timeout time.Duration
-	Remote  bool
+	Mirror   string
+	ServedBy string // 实际提供数据的镜像，故障转移后可能与 Mirror 不同
+	Cached   bool   // 数据是否来自本地缓存
+	Timeout  time.Duration
+	Remote   bool
+}
+
+// useResultMsg 是切换版本的结果，切换在 tea.Cmd 中进行，等待全局锁时界面不会卡住
+type useResultMsg struct {
+	item *version.Version
+	err  error
+}
+
+// lockWaitMsg 表示正在等待其它 gvm 进程释放全局锁
+type lockWaitMsg struct {
+	pid int
 }
 
 type uninstallResultMsg struct {
@@ -113,6 +127,7 @@ func NewListProgram(items []list.Item, title title, footer FooterInfo) *tea.Prog
 	}
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	model.program = program
+	lock.OnWait = func(pid int) { program.Send(lockWaitMsg{pid: pid}) }
 	return program
 }
 
@@ -147,6 +162,13 @@ func (m *Model) download(item *version.Version) {
 	m.clearError()
 }
 
+// useVersion 返回切换到 item 的 tea.Cmd
+func useVersion(item *version.Version) tea.Cmd {
+	return func() tea.Msg {
+		return useResultMsg{item: item, err: core.UseVersion(item.LocalDir())}
+	}
+}
+
 func (m *Model) processUninstall(item *version.Version) {
 	if item == nil {
 		return
@@ -228,7 +250,14 @@ func (m *Model) renderFooter() string {
 	if m.footer.Remote {
 		mode = "远程模式"
 	}
-	return footerStyle.Render(fmt.Sprintf(" %s · 镜像：%s · 超时：%s", mode, mirror, durationLabel(t)))
+	info := fmt.Sprintf(" %s · 镜像：%s · 超时：%s", mode, mirror, durationLabel(t))
+	if m.footer.ServedBy != "" {
+		info += " · 数据来源：" + m.footer.ServedBy
+		if m.footer.Cached {
+			info += "（缓存）"
+		}
+	}
+	return footerStyle.Render(info)
 }
 
 func (m *Model) renderLastError() string {
@@ -355,6 +384,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.list.SetItem(m.list.Index(), item)
 		}
 		return m, tea.Batch(m.list.NewStatusMessage(successMessageStyle("success uninstall " + item.String())))
+	case lockWaitMsg:
+		text := "waiting for another gvm process to finish..."
+		if msg.pid > 0 {
+			text = fmt.Sprintf("waiting for another gvm process (pid %d) to finish...", msg.pid)
+		}
+		return m, m.list.NewStatusMessage(warnMessageStyle(text))
+	case useResultMsg:
+		m.annotateStatus(msg.item, "")
+		if msg.err != nil {
+			return m, m.list.NewStatusMessage(failMessageStyle(msg.err.Error()))
+		}
+		for i, v := range m.list.Items() {
+			vi := v.(*version.Version)
+			if vi.CurrentUsed != (vi == msg.item) {
+				vi.CurrentUsed = vi == msg.item
+				cmds = append(cmds, m.list.SetItem(i, vi))
+			}
+		}
+		m.clearError()
+		cmds = append(cmds, m.list.NewStatusMessage(successMessageStyle("current use "+msg.item.String())))
+		return m, tea.Batch(cmds...)
 	case tea.WindowSizeMsg:
 		h, v := appStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
@@ -386,21 +436,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if item.CurrentUsed || item.DirName == "" {
 				return m, nil
 			}
-			err := core.SwitchVersion(item.LocalDir())
-			if err != nil {
-				return m, m.list.NewStatusMessage(failMessageStyle(err.Error()))
-			}
-			for i, v := range m.list.Items() {
-				if vi := v.(*version.Version); vi.CurrentUsed {
-					vi.CurrentUsed = false
-					m.list.SetItem(i, vi)
-				}
-			}
-			item.CurrentUsed = true
-			setCmd := m.list.SetItem(m.list.Index(), item)
-			statusCmd := m.list.NewStatusMessage(successMessageStyle("current use " + item.String()))
-			m.clearError()
-			return m, tea.Batch(setCmd, statusCmd)
+			m.annotateStatus(item, "切换中...")
+			return m, useVersion(item)
 		case key.Matches(msg, m.keys.retry):
 			if m.retryAction == nil {
 				return m, nil
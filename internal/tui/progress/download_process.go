@@ -54,8 +54,10 @@ func NewModel(program *tea.Program) *Model {
 	return m
 }
 
-func (m *Model) Start() {
-	m.program.Run()
+// Start 运行进度条直到退出，无法打开终端时返回 error
+func (m *Model) Start() error {
+	_, err := m.program.Run()
+	return err
 }
 
 func (m *Model) Quit() {
@@ -64,6 +66,7 @@ func (m *Model) Quit() {
 func (m *Model) SetSize(size int64) {
 	m.writer.total = size
 }
+
 func (m *Model) Size() int64 {
 	return m.written
 }
@@ -182,6 +185,13 @@ type ProgressWriter struct {
 func (pw *ProgressWriter) SetSize(size int64) {
 	pw.total = size
 }
+
+// SetOffset 设置已下载的字节数，速度只统计之后写入的数据
+func (pw *ProgressWriter) SetOffset(offset int64) {
+	pw.written = offset
+	pw.start = time.Now()
+	pw.speedHistory = pw.speedHistory[:0]
+}
 func (pw *ProgressWriter) Write(p []byte) (int, error) {
 	n := len(p)
 	pw.written += int64(n)
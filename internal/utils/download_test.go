@@ -1,8 +1,17 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 /*
@@ -14,10 +23,138 @@ import (
 
 func Test_Download(t *testing.T) {
 	sourceUrl := "https://golang.google.cn/dl/go1.24.6.darwin-arm64.tar.gz"
-	download, err := DownloadFile(sourceUrl, "./go1.24.6.darwin-arm64.tar.gz", os.O_RDWR|os.O_CREATE, 0600)
+	download, err := DownloadFile(sourceUrl, "./go1.24.6.darwin-arm64.tar.gz", 0600)
 	if err != nil {
 		t.Error(err.Error())
 		return
 	}
 	t.Log(download)
 }
+
+type offsetRecorder struct {
+	bytes.Buffer
+	offset int64
+}
+
+func (w *offsetRecorder) SetOffset(offset int64) { w.offset = offset }
+
+func TestDownloadResumable(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := os.WriteFile(PartFile(filename), content[:4000], 0644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		w     offsetRecorder
+		total int64
+	)
+	size, err := DownloadResumable(srv.URL, filename, 0644, &w, func(n int64) { total = n })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("size = %d, total = %d, want %d", size, total, len(content))
+	}
+	if w.offset != 4000 || w.Len() != len(content)-4000 {
+		t.Errorf("offset = %d, written = %d", w.offset, w.Len())
+	}
+	got, _ := os.ReadFile(filename)
+	if !bytes.Equal(got, content) {
+		t.Error("resumed file differs from source")
+	}
+	if _, err = os.Stat(PartFile(filename)); !os.IsNotExist(err) {
+		t.Error(".part file should be renamed")
+	}
+}
+
+func TestDownloadResumable_NoRangeSupport(t *testing.T) {
+	content := []byte(strings.Repeat("abcdef", 500))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := os.WriteFile(PartFile(filename), []byte("stale data from another file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DownloadResumable(srv.URL, filename, 0644, io.Discard, func(int64) {}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filename)
+	if !bytes.Equal(got, content) {
+		t.Error("full download should replace the stale .part file")
+	}
+}
+
+func TestDownloadResumable_RetryAfterDrop(t *testing.T) {
+	content := []byte(strings.Repeat("x", 8192))
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// 声明完整长度但只发送一半后断开
+			w.Header().Set("Content-Length", "8192")
+			w.Write(content[:4096])
+			return
+		}
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	var w offsetRecorder
+	if _, err := DownloadResumable(srv.URL, filename, 0644, &w, func(int64) {}, WithRetryDelay(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || w.offset != 4096 {
+		t.Errorf("requests = %d, offset = %d", requests, w.offset)
+	}
+	got, _ := os.ReadFile(filename)
+	if !bytes.Equal(got, content) {
+		t.Error("retried file differs from source")
+	}
+}
+
+type cancelWriter func()
+
+func (cancel cancelWriter) Write(p []byte) (int, error) {
+	cancel()
+	return len(p), nil
+}
+
+func TestDownloadResumable_Cancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "8192")
+		w.Write([]byte(strings.Repeat("x", 4096)))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	// 收到数据后取消，模拟下载中途退出进度条
+	ctx, cancel := context.WithCancel(context.Background())
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	_, err := DownloadResumable(srv.URL, filename, 0644, cancelWriter(cancel), func(int64) {}, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if info, err := os.Stat(PartFile(filename)); err != nil || info.Size() == 0 {
+		t.Errorf(".part should keep the downloaded data, err = %v", err)
+	}
+}
+
+func TestDownloadResumable_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	if _, err := DownloadResumable(srv.URL, filename, 0644, io.Discard, func(int64) {}); err == nil {
+		t.Fatal("expected error")
+	}
+}
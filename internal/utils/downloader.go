@@ -1,25 +1,35 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/the-yex/gvm/internal/transport"
 	progress2 "github.com/the-yex/gvm/internal/tui/progress"
 	"io"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 type SetSize func(int642 int64)
 
+// OffsetWriter 由进度条等 writer 实现，断点续传时告知已下载的字节数
+type OffsetWriter interface {
+	SetOffset(offset int64)
+}
+
+const checksumTimeout = 30 * time.Second
+
 func Download(srcURL string, writer io.Writer, fn SetSize) (int64, error) {
-	req, err := http.NewRequest(http.MethodGet, srcURL, nil)
-	if err != nil {
-		return 0, fmt.Errorf("resource(%s) download failed ==> %s", srcURL, err.Error())
+	if path, ok := FilePath(srcURL); ok {
+		return copyLocalFile(path, writer, fn)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := transport.Get(srcURL, 0)
 	if err != nil {
 		return 0, fmt.Errorf("resource(%s) download failed ==> %s", srcURL, err.Error())
 	}
@@ -31,21 +41,93 @@ func Download(srcURL string, writer io.Writer, fn SetSize) (int64, error) {
 	return io.Copy(writer, resp.Body)
 }
 
-func DownloadFile(srcURL, filename string, flag int, perm fs.FileMode) (int64, error) {
-	f, err := os.OpenFile(filename, flag, perm)
+// FetchChecksum 下载 .sha256 等校验文件并返回其中的十六进制摘要
+func FetchChecksum(srcURL string) (string, error) {
+	if path, ok := FilePath(srcURL); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("checksum(%s) read failed ==> %s", srcURL, err.Error())
+		}
+		return parseChecksum(srcURL, data)
+	}
+	resp, err := transport.Get(srcURL, checksumTimeout)
 	if err != nil {
-		return 0, fmt.Errorf("resource(%s) download failed ==> %s", srcURL, err.Error())
+		return "", fmt.Errorf("checksum(%s) download failed ==> %s", srcURL, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("URL %q is unreachable  ==> %d", srcURL, resp.StatusCode)
+	}
+	// sidecar 文件格式为 "<hex>" 或 "<hex>  <filename>"
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	return parseChecksum(srcURL, data)
+}
+
+func parseChecksum(srcURL string, data []byte) (string, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum(%s) is empty: %w", srcURL, ErrChecksumUnavailable)
+	}
+	return fields[0], nil
+}
+
+// FilePath 将 file:// URL 转换为本地路径，非 file:// URL 返回 false
+func FilePath(srcURL string) (string, bool) {
+	if !strings.HasPrefix(srcURL, "file://") {
+		return "", false
+	}
+	u, err := url.Parse(srcURL)
+	if err != nil {
+		return "", false
+	}
+	path := u.Path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' { // file:///C:/go
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
+
+// copyLocalFile 以与 HTTP 下载相同的方式读取本地文件，用于离线镜像
+func copyLocalFile(path string, writer io.Writer, fn SetSize) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("resource(%s) read failed ==> %s", path, err.Error())
 	}
 	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	fn(info.Size())
+	return io.Copy(writer, f)
+}
+
+// DownloadFile 带进度条下载文件，支持断点续传；用户取消时中止下载并保留 .part 文件以便下次继续
+func DownloadFile(srcURL, filename string, perm fs.FileMode) (int64, error) {
 	model := progress2.NewModel(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	type result struct {
+		size int64
+		err  error
+	}
+	done := make(chan result, 1)
 	go func() {
-		Download(srcURL, model.MultiWriter(f), model.SetSize)
+		size, err := DownloadResumable(srcURL, filename, perm, model.MultiWriter(nil), model.SetSize, WithContext(ctx))
+		done <- result{size, err}
 		model.Quit()
 	}()
-	model.Start()
+	if err := model.Start(); err == nil {
+		// 进度条退出后取消仍在进行的下载，等待其写完 .part 再返回
+		cancel()
+	}
+	// 进度条无法启动（如没有终端）时不显示进度，等待下载完成
+	res := <-done
 	if model.IsCancel() {
-		os.Remove(filename)
 		return 0, errors.New("download cancel")
 	}
-	return model.Size(), nil
+	return res.size, res.err
 }
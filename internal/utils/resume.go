@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/the-yex/gvm/internal/transport"
+)
+
+const (
+	// PartSuffix 未下载完成的文件后缀
+	PartSuffix = ".part"
+	// downloadAttempts 网络中断时的最大尝试次数
+	downloadAttempts = 5
+)
+
+// ResumeOption DownloadResumable 的可选参数
+type ResumeOption struct {
+	// Context 取消后中止下载与重试，已下载的数据保留在 .part 中
+	Context context.Context
+	// RetryDelay 每次重试前等待 RetryDelay * 已失败次数
+	RetryDelay time.Duration
+}
+
+// WithContext 使用 ctx 控制下载的取消
+func WithContext(ctx context.Context) func(option *ResumeOption) {
+	return func(option *ResumeOption) {
+		option.Context = ctx
+	}
+}
+
+// WithRetryDelay 设置重试的间隔基数，默认为 1 秒
+func WithRetryDelay(delay time.Duration) func(option *ResumeOption) {
+	return func(option *ResumeOption) {
+		option.RetryDelay = delay
+	}
+}
+
+// errNotRetryable 标记无需重试的错误，如 404
+type errNotRetryable struct{ error }
+
+func (e errNotRetryable) Unwrap() error { return e.error }
+
+// PartFile 返回 filename 对应的临时下载文件
+func PartFile(filename string) string {
+	return filename + PartSuffix
+}
+
+// DownloadResumable 将 srcURL 下载到 filename。
+// 数据先写入 filename.part，已存在的 .part 会通过 Range 请求续传，服务端不支持 Range 时重新完整下载；
+// 网络中断会自动重试，重试失败时保留 .part 供下次继续。下载完成后 .part 重命名为 filename。
+// writer 只接收本次新下载的数据，若实现了 OffsetWriter 会先收到已有的字节数。
+func DownloadResumable(srcURL, filename string, perm fs.FileMode, writer io.Writer, fn SetSize, opts ...func(option *ResumeOption)) (int64, error) {
+	opt := &ResumeOption{Context: context.Background(), RetryDelay: time.Second}
+	for _, o := range opts {
+		o(opt)
+	}
+	part := PartFile(filename)
+	if path, ok := FilePath(srcURL); ok {
+		return copyToFile(path, part, filename, perm, writer, fn)
+	}
+
+	var errs []error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		size, err := resumeOnce(opt.Context, srcURL, part, perm, writer, fn)
+		if err == nil {
+			if err = os.Rename(part, filename); err != nil {
+				return 0, err
+			}
+			return size, nil
+		}
+		errs = append(errs, err)
+		var fatal errNotRetryable
+		if errors.As(err, &fatal) || opt.Context.Err() != nil || attempt == downloadAttempts {
+			break
+		}
+		select {
+		case <-opt.Context.Done():
+		case <-time.After(opt.RetryDelay * time.Duration(attempt)):
+		}
+	}
+	return 0, fmt.Errorf("resource(%s) download failed ==> %w", srcURL, errors.Join(errs...))
+}
+
+// resumeOnce 进行一次下载尝试，返回文件的完整大小
+func resumeOnce(ctx context.Context, srcURL, part string, perm fs.FileMode, writer io.Writer, fn SetSize) (int64, error) {
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+	req, err := transport.NewRequest(http.MethodGet, srcURL)
+	if err != nil {
+		return 0, errNotRetryable{err}
+	}
+	req = req.WithContext(ctx)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := transport.Do(req, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// 服务端返回的区间与本地不一致，丢弃 .part 重新下载
+			os.Remove(part)
+			return 0, fmt.Errorf("unexpected Content-Range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+		}
+		if total < 0 {
+			total = offset + resp.ContentLength
+		}
+		flag |= os.O_APPEND
+		reportOffset(writer, offset)
+		fn(total)
+	case http.StatusOK:
+		// 服务端不支持 Range，从头开始
+		offset = 0
+		flag |= os.O_TRUNC
+		reportOffset(writer, 0)
+		fn(resp.ContentLength)
+	case http.StatusRequestedRangeNotSatisfiable:
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && total == offset {
+			reportOffset(writer, offset)
+			fn(total)
+			return total, nil // .part 已经完整
+		}
+		os.Remove(part)
+		return 0, fmt.Errorf("URL %q rejected range from %d", srcURL, offset)
+	default:
+		err = fmt.Errorf("URL %q is unreachable  ==> %d", srcURL, resp.StatusCode)
+		if resp.StatusCode < http.StatusInternalServerError {
+			return 0, errNotRetryable{err}
+		}
+		return 0, err
+	}
+
+	f, err := os.OpenFile(part, flag, perm)
+	if err != nil {
+		return 0, errNotRetryable{err}
+	}
+	defer f.Close()
+	n, err := io.Copy(io.MultiWriter(f, writer), resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.ContentLength >= 0 && n < resp.ContentLength {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return offset + n, nil
+}
+
+// copyToFile 复制离线镜像中的文件，同样经过 .part 以免留下不完整的文件
+func copyToFile(path, part, filename string, perm fs.FileMode, writer io.Writer, fn SetSize) (int64, error) {
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return 0, err
+	}
+	reportOffset(writer, 0)
+	n, err := copyLocalFile(path, io.MultiWriter(f, writer), fn)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(part)
+		return 0, err
+	}
+	return n, os.Rename(part, filename)
+}
+
+func reportOffset(writer io.Writer, offset int64) {
+	if w, ok := writer.(OffsetWriter); ok {
+		w.SetOffset(offset)
+	}
+}
+
+// parseContentRange 解析 "bytes 100-199/200" 或 "bytes */200"，总大小未知时 total 为 -1
+func parseContentRange(header string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	total = -1
+	if size != "*" {
+		var err error
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rng == "*" {
+		return 0, total, true
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
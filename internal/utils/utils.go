@@ -5,11 +5,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"hash"
 	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 )
 
 /*
@@ -41,6 +43,33 @@ const (
 	SHA1 Algorithm = "SHA1"
 )
 
+// ErrChecksumUnavailable is returned when no checksum can be found for a file.
+var ErrChecksumUnavailable = errors.New("checksum unavailable")
+
+// ChecksumMismatchError reports a file whose computed checksum differs from the expected one.
+type ChecksumMismatchError struct {
+	File      string
+	Algorithm Algorithm
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch for %s: expected %s, got %s", e.Algorithm, e.File, e.Expected, e.Actual)
+}
+
+// ParseAlgorithm normalizes algorithm names such as "sha256" or "SHA-256".
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch Algorithm(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", ""))) {
+	case SHA256:
+		return SHA256, nil
+	case SHA1:
+		return SHA1, nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q", s)
+	}
+}
+
 // VerifyFile validates file integrity against expected checksum.
 func VerifyFile(algo Algorithm, expectedChecksum, filename string) (err error) {
 	f, err := os.Open(filename)
@@ -63,8 +92,9 @@ func VerifyFile(algo Algorithm, expectedChecksum, filename string) (err error) {
 		return err
 	}
 
-	if expectedChecksum != hex.EncodeToString(h.Sum(nil)) {
-		return errors.New("file checksum does not match the computed checksum")
+	expected := strings.ToLower(strings.TrimSpace(expectedChecksum))
+	if actual := hex.EncodeToString(h.Sum(nil)); expected != actual {
+		return &ChecksumMismatchError{File: filename, Algorithm: algo, Expected: expected, Actual: actual}
 	}
 	return nil
 }
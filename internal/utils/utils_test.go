@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := os.WriteFile(filename, []byte("gvm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("gvm"))
+	expected := hex.EncodeToString(sum[:])
+
+	if err := VerifyFile(SHA256, expected, filename); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := VerifyFile(SHA256, "deadbeef", filename)
+	var mismatch *ChecksumMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected ChecksumMismatchError, got %v", err)
+	}
+	if mismatch.Actual != expected {
+		t.Errorf("actual checksum = %s, want %s", mismatch.Actual, expected)
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Algorithm
+		wantErr bool
+	}{
+		{"SHA256", SHA256, false},
+		{"sha-256", SHA256, false},
+		{"sha1", SHA1, false},
+		{"md5", "", true},
+	}
+	for _, tc := range tests {
+		got, err := ParseAlgorithm(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("ParseAlgorithm(%q) = %q, %v", tc.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"runtime"
+	"sort"
+)
+
+// Filter 描述 gvm list 的筛选条件，零值不做任何筛选
+type Filter struct {
+	// Constraint 不为空时只保留满足约束的版本，如 ">=1.21 <1.23"
+	Constraint *Constraints
+	// Installed 与 NotInstalled 只保留已安装或未安装的版本
+	Installed    bool
+	NotInstalled bool
+	// OS 与 Arch 不为空时只保留有该平台二进制归档包的版本，为空的一项取当前平台
+	OS, Arch string
+	// LatestPerMinor 每个次版本线只保留最新的版本
+	LatestPerMinor bool
+}
+
+// Apply 返回满足筛选条件的版本，保持原有顺序
+func (f Filter) Apply(versions []*Version) []*Version {
+	goos, goarch := runtime.GOOS, runtime.GOARCH
+	if f.OS != "" {
+		goos = f.OS
+	}
+	if f.Arch != "" {
+		goarch = f.Arch
+	}
+	filtered := make([]*Version, 0, len(versions))
+	for _, v := range versions {
+		switch {
+		case f.Constraint != nil && !f.Constraint.Check(v):
+		case f.Installed && !v.Installed:
+		case f.NotInstalled && v.Installed:
+		case (f.OS != "" || f.Arch != "") && !hasArtifact(v, goos, goarch):
+		default:
+			filtered = append(filtered, v)
+		}
+	}
+	if f.LatestPerMinor {
+		filtered = latestPerMinor(filtered)
+	}
+	return filtered
+}
+
+func hasArtifact(v *Version, goos, goarch string) bool {
+	_, err := v.ArtifactFor(goos, goarch)
+	return err == nil
+}
+
+// latestPerMinor 每个次版本线只保留最新的版本（正式版优先于同一次版本的预发布版本）
+func latestPerMinor(versions []*Version) []*Version {
+	sorted := make([]*Version, len(versions))
+	copy(sorted, versions)
+	sort.Sort(sort.Reverse(Collection(sorted)))
+
+	latest := make(map[[2]uint64]*Version)
+	for _, v := range sorted {
+		line := [2]uint64{v.Major(), v.Minor()}
+		if _, ok := latest[line]; !ok {
+			latest[line] = v
+		}
+	}
+	result := make([]*Version, 0, len(latest))
+	for _, v := range versions {
+		if latest[[2]uint64{v.Major(), v.Minor()}] == v {
+			result = append(result, v)
+		}
+	}
+	return result
+}
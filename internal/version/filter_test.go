@@ -0,0 +1,64 @@
+package version
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func filterFixture(t *testing.T) []*Version {
+	t.Helper()
+	var versions []*Version
+	for _, name := range []string{"go1.23rc1", "go1.22.5", "go1.22.4", "go1.21.12", "go1.21.0", "go1.20.14"} {
+		platforms := []string{"linux-amd64"}
+		if name != "go1.20.14" {
+			platforms = append(platforms, "darwin-arm64")
+		}
+		var artifacts []ArtifactInfo
+		for _, p := range platforms {
+			artifacts = append(artifacts, ArtifactInfo{FileName: fmt.Sprintf("%s.%s.tar.gz", name, p), Kind: ArchiveKind})
+		}
+		v, err := NewGoVersion(name, WithArtifacts(artifacts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.Installed = name == "go1.22.4" || name == "go1.21.0"
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func versionStrings(versions []*Version) []string {
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		names = append(names, v.String())
+	}
+	return names
+}
+
+func TestFilter_Apply(t *testing.T) {
+	constraint, err := NewConstraint(">=1.21 <1.23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"none", Filter{}, []string{"1.23.0-rc1", "1.22.5", "1.22.4", "1.21.12", "1.21.0", "1.20.14"}},
+		{"constraint", Filter{Constraint: constraint}, []string{"1.22.5", "1.22.4", "1.21.12", "1.21.0"}},
+		{"installed", Filter{Installed: true}, []string{"1.22.4", "1.21.0"}},
+		{"not installed", Filter{NotInstalled: true, Constraint: constraint}, []string{"1.22.5", "1.21.12"}},
+		{"platform", Filter{OS: "darwin", Arch: "arm64"}, []string{"1.23.0-rc1", "1.22.5", "1.22.4", "1.21.12", "1.21.0"}},
+		{"unknown platform", Filter{OS: "plan9", Arch: "386"}, []string{}},
+		{"latest per minor", Filter{LatestPerMinor: true}, []string{"1.23.0-rc1", "1.22.5", "1.21.12", "1.20.14"}},
+		{"installed latest per minor", Filter{Installed: true, LatestPerMinor: true}, []string{"1.22.4", "1.21.0"}},
+	}
+	for _, tt := range tests {
+		got := versionStrings(tt.filter.Apply(filterFixture(t)))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/the-yex/gvm/internal/core"
 	"runtime"
 	"sort"
+	"strings"
 )
 
 type Finder struct {
@@ -85,6 +86,41 @@ func (fdr *Finder) Find(vname string) (*Version, error) {
 	return nil, fmt.Errorf("version not found %q [%s,%s]", vname, fdr.goos, fdr.goarch)
 }
 
+// FindLatestMatch 与 Find 类似但不会弹出选择界面，用于按项目锁定的版本安装：
+// vname 为 1.22.3、1.21rc1 这样的具体版本时精确匹配，为 1.21 或约束表达式时返回满足条件的最高版本。
+func (fdr *Finder) FindLatestMatch(vname string) (*Version, error) {
+	if vname == Latest {
+		return fdr.findLatest()
+	}
+	name := strings.TrimPrefix(vname, "go")
+	if target, err := NewGoVersion(name); err == nil && (hasPatch(name) || target.Prerelease() != "") {
+		for i := len(fdr.items) - 1; i >= 0; i-- {
+			if fdr.items[i].Equal(target) && fdr.items[i].match(fdr.goos, fdr.goarch) {
+				return fdr.items[i], nil
+			}
+		}
+		return nil, fmt.Errorf("version not found %q [%s,%s]", vname, fdr.goos, fdr.goarch)
+	}
+
+	cs, err := NewConstraint(name)
+	if err != nil {
+		return nil, fmt.Errorf("version not found %q [%s,%s]", vname, fdr.goos, fdr.goarch)
+	}
+	for i := len(fdr.items) - 1; i >= 0; i-- {
+		if cs.Check(fdr.items[i]) && fdr.items[i].match(fdr.goos, fdr.goarch) {
+			return fdr.items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version not found %q [%s,%s]", vname, fdr.goos, fdr.goarch)
+}
+
+// hasPatch 报告版本号是否包含补丁号，如 1.21.0
+func hasPatch(v string) bool {
+	v, _, _ = strings.Cut(v, "-")
+	v, _, _ = strings.Cut(v, "+")
+	return strings.Count(v, ".") >= 2
+}
+
 // MustFind returns matched version or panics on error.
 func (fdr *Finder) MustFind(vname string) *Version {
 	v, err := fdr.Find(vname)
@@ -1,10 +1,15 @@
 package version
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/mholt/archiver/v3"
 	"github.com/the-yex/gvm/internal/consts"
@@ -113,14 +118,19 @@ type ArtifactInfo struct {
 	Arch        ARCH   `json:"arch"`     // 架构
 	Size        string `json:"size"`     // 文件大小（字节）
 	Checksum    string `json:"checksum"`
-	ChecksumURL string `json:"-"`
+	ChecksumURL string `json:"checksum_url,omitempty"`
 	Algorithm   string `json:"algorithm"`
 }
 
-// Clean 清理安装过程中的垃圾文件
+// installTempPrefix 安装时的临时解压目录前缀，位于 VERSION_DIR 下
+const installTempPrefix = ".install-"
+
+// staleInstallAge 超过该时长的临时解压目录视为被中断的安装，会被清理
+const staleInstallAge = 24 * time.Hour
+
+// Clean 清理安装过程中的垃圾文件，未下载完成的 .part 文件会保留用于断点续传
 func (artifactInfo ArtifactInfo) clean() {
 	os.Remove(artifactInfo.localFile())
-	os.RemoveAll(filepath.Join(consts.VERSION_DIR, "go"))
 }
 
 // Install 解压文件并安装版本到本地
@@ -130,29 +140,157 @@ func (artifactInfo ArtifactInfo) Install(version string) error {
 	if nil != err {
 		return err
 	}
-	err = archiver.Unarchive(artifactInfo.localFile(), consts.VERSION_DIR)
-	if nil != err {
+	if err = artifactInfo.verify(); err != nil {
 		return err
 	}
-	return os.Rename(filepath.Join(consts.VERSION_DIR, "go"), filepath.Join(consts.VERSION_DIR, fmt.Sprintf("go%s", version)))
+	return artifactInfo.extract(version)
 }
 
 func (artifactInfo ArtifactInfo) MultiWriterInstall(version string, writer io.Writer, fn func(int642 int64)) error {
 	defer artifactInfo.clean()
-	f, err := os.OpenFile(artifactInfo.localFile(), os.O_CREATE|os.O_WRONLY, 0644)
+	_, err := utils.DownloadResumable(artifactInfo.URL, artifactInfo.localFile(), 0644, writer, fn)
+	if nil != err {
+		return err
+	}
+	if err = artifactInfo.verify(); err != nil {
+		return err
+	}
+	return artifactInfo.extract(version)
+}
+
+// extract 将安装包解压到独立的临时目录，检查解压结果确实是 go<version> 后再重命名到最终位置。
+// 任何一步失败都会删除临时目录，VERSION_DIR 中不会出现解压了一半的版本。
+func (artifactInfo ArtifactInfo) extract(version string) error {
+	target := filepath.Join(consts.VERSION_DIR, fmt.Sprintf("go%s", version))
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s already exists", target)
+	}
+	pruneStaleInstalls()
+
+	tmp, err := os.MkdirTemp(consts.VERSION_DIR, fmt.Sprintf("%sgo%s-", installTempPrefix, version))
 	if err != nil {
-		return fmt.Errorf("resource(%s) download failed ==> %s", artifactInfo.URL, err.Error())
+		return err
 	}
-	defer f.Close()
-	_, err = utils.Download(artifactInfo.URL, io.MultiWriter(f, writer), fn)
-	if nil != err {
+	defer os.RemoveAll(tmp)
+
+	if err = archiver.Unarchive(artifactInfo.localFile(), tmp); err != nil {
 		return err
 	}
-	err = archiver.Unarchive(artifactInfo.localFile(), consts.VERSION_DIR)
-	if nil != err {
+	goRoot := filepath.Join(tmp, "go")
+	if err = artifactInfo.checkGoRoot(goRoot, version); err != nil {
+		return err
+	}
+	return os.Rename(goRoot, target)
+}
+
+// checkGoRoot 确认解压出的目录是完整的 go<version>：
+// 与当前平台一致时执行 bin/go version，否则读取 VERSION 文件
+func (artifactInfo ArtifactInfo) checkGoRoot(goRoot, version string) error {
+	goBin := filepath.Join(goRoot, "bin", "go")
+	if runtime.GOOS == "windows" {
+		goBin += ".exe"
+	}
+	if _, err := os.Stat(goBin); err != nil {
+		return fmt.Errorf("%s does not contain a Go distribution: %w", artifactInfo.FileName, err)
+	}
+
+	want := "go" + version
+	var got string
+	if string(artifactInfo.OS) == runtime.GOOS && string(artifactInfo.Arch) == runtime.GOARCH {
+		cmd := exec.Command(goBin, "version")
+		cmd.Dir = goRoot
+		cmd.Env = append(os.Environ(), "GOROOT="+goRoot, "GOTOOLCHAIN=local")
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("%s version failed: %w", goBin, err)
+		}
+		// go version go1.22.1 linux/amd64
+		if fields := strings.Fields(string(out)); len(fields) >= 3 {
+			got = fields[2]
+		}
+	} else {
+		data, err := os.ReadFile(filepath.Join(goRoot, "VERSION"))
+		if err != nil {
+			return fmt.Errorf("%s does not contain a Go distribution: %w", artifactInfo.FileName, err)
+		}
+		got, _, _ = strings.Cut(string(data), "\n")
+		got = strings.TrimSpace(got)
+	}
+	// go1.20 的 VERSION 中没有补丁号，预发布版本写作 go1.21rc1，按版本号比较而不是字符串
+	if !sameGoVersion(got, want) {
+		return fmt.Errorf("%s contains %q, expected %q", artifactInfo.FileName, got, want)
+	}
+	return nil
+}
+
+func sameGoVersion(a, b string) bool {
+	va, errA := NewGoVersion(a)
+	vb, errB := NewGoVersion(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return va.Equal(vb)
+}
+
+// pruneStaleInstalls 删除被中断（如 Ctrl-C）的安装遗留的临时目录
+func pruneStaleInstalls() {
+	entries, err := os.ReadDir(consts.VERSION_DIR)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), installTempPrefix) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > staleInstallAge {
+			os.RemoveAll(filepath.Join(consts.VERSION_DIR, entry.Name()))
+		}
+	}
+}
+
+// verify 校验已下载的安装包，校验失败时删除本地文件
+func (artifactInfo ArtifactInfo) verify() error {
+	algo, expected, err := artifactInfo.expectedChecksum()
+	if err != nil {
+		os.Remove(artifactInfo.localFile())
+		return err
+	}
+	if err = utils.VerifyFile(algo, expected, artifactInfo.localFile()); err != nil {
+		os.Remove(artifactInfo.localFile())
 		return err
 	}
-	return os.Rename(filepath.Join(consts.VERSION_DIR, "go"), filepath.Join(consts.VERSION_DIR, fmt.Sprintf("go%s", version)))
+	return nil
+}
+
+// expectedChecksum 优先使用页面内联的校验和，其次下载镜像提供的 .sha256 文件
+func (artifactInfo ArtifactInfo) expectedChecksum() (utils.Algorithm, string, error) {
+	algorithm := artifactInfo.Algorithm
+	if algorithm == "" {
+		algorithm = string(utils.SHA256)
+	}
+	algo, err := utils.ParseAlgorithm(algorithm)
+	if err != nil {
+		return "", "", err
+	}
+	if artifactInfo.Checksum != "" {
+		return algo, artifactInfo.Checksum, nil
+	}
+	checksumURLs := []string{artifactInfo.ChecksumURL}
+	if algo == utils.SHA256 {
+		checksumURLs = append(checksumURLs, artifactInfo.URL+".sha256")
+	}
+	var errs []error
+	for _, checksumURL := range checksumURLs {
+		if checksumURL == "" {
+			continue
+		}
+		checksum, err := utils.FetchChecksum(checksumURL)
+		if err == nil {
+			return algo, checksum, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", "", fmt.Errorf("%s: %w", artifactInfo.FileName, errors.Join(append([]error{utils.ErrChecksumUnavailable}, errs...)...))
 }
 
 func (artifactInfo ArtifactInfo) localFile() string {
@@ -160,5 +298,5 @@ func (artifactInfo ArtifactInfo) localFile() string {
 }
 
 func (artifactInfo ArtifactInfo) download() (size int64, err error) {
-	return utils.DownloadFile(artifactInfo.URL, artifactInfo.localFile(), os.O_CREATE|os.O_WRONLY, 0644)
+	return utils.DownloadFile(artifactInfo.URL, artifactInfo.localFile(), 0644)
 }
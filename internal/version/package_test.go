@@ -0,0 +1,165 @@
+package version
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/utils"
+)
+
+func TestArtifactInfo_expectedChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/go1.22.0.linux-amd64.tar.gz.sha256":
+			fmt.Fprintln(w, "abc123  go1.22.0.linux-amd64.tar.gz")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	inline := ArtifactInfo{FileName: "go1.22.0.linux-amd64.tar.gz", Checksum: "inline", Algorithm: "SHA256"}
+	if _, sum, err := inline.expectedChecksum(); err != nil || sum != "inline" {
+		t.Fatalf("inline checksum = %q, %v", sum, err)
+	}
+
+	sidecar := ArtifactInfo{
+		FileName: "go1.22.0.linux-amd64.tar.gz",
+		URL:      srv.URL + "/go1.22.0.linux-amd64.tar.gz",
+	}
+	if algo, sum, err := sidecar.expectedChecksum(); err != nil || sum != "abc123" || algo != utils.SHA256 {
+		t.Fatalf("sidecar checksum = %s %q, %v", algo, sum, err)
+	}
+
+	missing := ArtifactInfo{FileName: "go1.21.0.linux-amd64.tar.gz", URL: srv.URL + "/go1.21.0.linux-amd64.tar.gz"}
+	if _, _, err := missing.expectedChecksum(); !errors.Is(err, utils.ErrChecksumUnavailable) {
+		t.Fatalf("expected ErrChecksumUnavailable, got %v", err)
+	}
+}
+
+// writeGoArchive 生成一个最小的 go 发行包：VERSION 文件与输出 go version 的 bin/go 脚本
+func writeGoArchive(t *testing.T, dir, fileName, goVersion string) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(dir, fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		"go/VERSION": goVersion + "\ntime 2025-01-01T00:00:00Z\n",
+		"go/bin/go":  fmt.Sprintf("#!/bin/sh\necho go version %s %s/%s\n", goVersion, runtime.GOOS, runtime.GOARCH),
+	}
+	for name, body := range files {
+		if err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func useVersionDir(t *testing.T) string {
+	t.Helper()
+	old := consts.VERSION_DIR
+	consts.VERSION_DIR = t.TempDir()
+	t.Cleanup(func() { consts.VERSION_DIR = old })
+	return consts.VERSION_DIR
+}
+
+func TestArtifactInfo_extract(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bin/go is a shell script")
+	}
+	dir := useVersionDir(t)
+	artifact := ArtifactInfo{FileName: "go1.22.1.test.tar.gz", OS: OS(runtime.GOOS), Arch: ARCH(runtime.GOARCH)}
+	writeGoArchive(t, dir, artifact.FileName, "go1.22.1")
+
+	if err := artifact.extract("1.22.1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go1.22.1", "bin", "go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := artifact.extract("1.22.1"); err == nil {
+		t.Error("expected error when the version already exists")
+	}
+	assertNoTempDirs(t, dir)
+}
+
+func TestArtifactInfo_extract_VersionMismatch(t *testing.T) {
+	dir := useVersionDir(t)
+	// 非当前平台的安装包通过 VERSION 文件校验
+	artifact := ArtifactInfo{FileName: "go1.22.1.plan9-386.tar.gz", OS: Plan9, Arch: I386}
+	writeGoArchive(t, dir, artifact.FileName, "go1.21.0")
+
+	if err := artifact.extract("1.22.1"); err == nil {
+		t.Fatal("expected version mismatch error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go1.22.1")); !os.IsNotExist(err) {
+		t.Error("mismatched version must not be installed")
+	}
+	assertNoTempDirs(t, dir)
+}
+
+func TestPruneStaleInstalls(t *testing.T) {
+	dir := useVersionDir(t)
+	stale := filepath.Join(dir, installTempPrefix+"go1.20.0-1")
+	fresh := filepath.Join(dir, installTempPrefix+"go1.21.0-2")
+	for _, d := range []string{stale, fresh} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleInstallAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+	pruneStaleInstalls()
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale install dir should be removed")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("in-progress install dir should be kept")
+	}
+}
+
+func assertNoTempDirs(t *testing.T, dir string) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), installTempPrefix) {
+			t.Errorf("temporary dir %s left behind", entry.Name())
+		}
+	}
+}
+
+func TestArtifactInfo_extract_GoVersionNames(t *testing.T) {
+	dir := useVersionDir(t)
+	for goVersion, installed := range map[string]string{"go1.20": "1.20.0", "go1.21rc2": "1.21.0-rc2"} {
+		artifact := ArtifactInfo{FileName: goVersion + ".plan9-386.tar.gz", OS: Plan9, Arch: I386}
+		writeGoArchive(t, dir, artifact.FileName, goVersion)
+		if err := artifact.extract(installed); err != nil {
+			t.Errorf("%s: %v", goVersion, err)
+		}
+	}
+}
@@ -245,15 +245,17 @@ func NewGoVersion(versionName string, opts ...func(v *Version)) (*Version, error
 	preTags := []string{"alpha", "beta", "rc"}
 	for _, tag := range preTags {
 		if idx := strings.Index(vName, tag); idx > 0 {
-			vName = vName[:idx] + "-" + vName[idx:]
+			if vName[idx-1] != '-' {
+				vName = vName[:idx] + "-" + vName[idx:]
+			}
 			break
 		}
 	}
 	version, err := NewVersion(vName)
-	version.original = versionName
 	if err != nil {
 		return nil, err
 	}
+	version.original = versionName
 	for _, opt := range opts {
 		if opt != nil {
 			opt(version)
@@ -368,12 +370,14 @@ func (v *Version) FindArtifact() (artifactInfo ArtifactInfo, err error) {
 }
 
 func (v *Version) findArtifact() (artifactInfo ArtifactInfo, err error) {
-	var (
-		kind   = ArchiveKind
-		goos   = runtime.GOOS
-		goarch = runtime.GOARCH
-	)
-	prefix := fmt.Sprintf("%s.%s-%s", v.original, goos, goarch)
+	return v.ArtifactFor(runtime.GOOS, runtime.GOARCH)
+}
+
+// ArtifactFor 返回该版本适用于 goos/goarch 的二进制归档包
+func (v *Version) ArtifactFor(goos, goarch string) (artifactInfo ArtifactInfo, err error) {
+	kind := ArchiveKind
+	// 目录类镜像从文件名中解析出的版本不带 go 前缀，安装包文件名总是以 go 开头
+	prefix := fmt.Sprintf("go%s.%s-%s", strings.TrimPrefix(v.original, "go"), goos, goarch)
 	for i := range v.Artifacts {
 		if !strings.EqualFold(string(v.Artifacts[i].Kind), string(kind)) || !strings.HasPrefix(v.Artifacts[i].FileName, prefix) {
 			continue
@@ -1,6 +1,8 @@
 package version
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
 )
 
@@ -44,3 +46,57 @@ func TestValidateMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestFinder_FindLatestMatch(t *testing.T) {
+	var items []*Version
+	for _, name := range []string{"go1.20", "go1.21rc2", "go1.21.0", "go1.21.5", "go1.22.0"} {
+		file := fmt.Sprintf("%s.%s-%s.tar.gz", name, runtime.GOOS, runtime.GOARCH)
+		v, err := NewGoVersion(name, WithArtifacts([]ArtifactInfo{{FileName: file, Kind: ArchiveKind}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, v)
+	}
+	fdr := NewFinder(items)
+	for query, want := range map[string]string{
+		"1.21":    "go1.21.5",
+		"1.21.0":  "go1.21.0",
+		"go1.20":  "go1.20",
+		"1.20.0":  "go1.20",
+		"1.21rc2": "go1.21rc2",
+		"~1.22":   "go1.22.0",
+		"latest":  "go1.22.0",
+	} {
+		v, err := fdr.FindLatestMatch(query)
+		if err != nil {
+			t.Errorf("FindLatestMatch(%q): %v", query, err)
+			continue
+		}
+		if v.Original() != want {
+			t.Errorf("FindLatestMatch(%q) = %s, want %s", query, v.Original(), want)
+		}
+	}
+	if _, err := fdr.FindLatestMatch("1.19"); err == nil {
+		t.Error("expected error for missing version")
+	}
+}
+
+func TestArtifactFor(t *testing.T) {
+	artifacts := []ArtifactInfo{
+		{FileName: "go1.22.5.src.tar.gz", Kind: SourceKind},
+		{FileName: "go1.22.5.linux-amd64.tar.gz", Kind: ArchiveKind, OS: "linux", Arch: AMD64},
+	}
+	// 官方索引的版本名带 go 前缀，目录类镜像从文件名解析出的不带
+	for _, name := range []string{"go1.22.5", "1.22.5"} {
+		v, err := NewGoVersion(name, WithArtifacts(artifacts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, err := v.ArtifactFor("linux", "amd64"); err != nil || a.FileName != "go1.22.5.linux-amd64.tar.gz" {
+			t.Errorf("%s: ArtifactFor = %+v, %v", name, a, err)
+		}
+		if _, err = v.ArtifactFor("darwin", "arm64"); err == nil {
+			t.Errorf("%s: expected error for missing platform", name)
+		}
+	}
+}
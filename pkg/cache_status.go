@@ -7,6 +7,7 @@ import (
 
 type RemoteCacheInfo struct {
 	Mirror   string
+	ServedBy string    // mirror that actually served the data, may differ from Mirror after failover
 	Used     bool      // true if cached data was used
 	Created  time.Time // cache creation time (if Used) or last fetch time
 	Forced   bool      // true when --refresh was requested
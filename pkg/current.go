@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/internal/shim"
+)
+
+// Status 描述当前目录下 gvm 认为生效的 Go 版本，以及 PATH 中的 go 是否确实指向它
+type Status struct {
+	Version   string `json:"version,omitempty"` // 生效的版本，未安装或未选择时为空
+	Request   string `json:"request,omitempty"` // 环境变量或项目文件请求的版本
+	Source    string `json:"source"`            // GVM_GO_VERSION、项目文件路径或 global
+	GoRoot    string `json:"goroot"`
+	Installed bool   `json:"installed"`
+	// PathGo 是 PATH 中第一个 go 命令，PathMatches 报告它是否会运行 GoRoot 中的 go（gvm shim 按目录解析，视为匹配）
+	PathGo      string `json:"path_go,omitempty"`
+	PathMatches bool   `json:"path_matches"`
+	ViaShim     bool   `json:"via_shim,omitempty"`
+}
+
+// Current 返回 dir 下生效的 Go 版本及其来源
+func Current(dir string) (Status, error) {
+	res, err := Resolve(dir)
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{
+		Request:   res.Request,
+		Source:    res.Source,
+		GoRoot:    res.GoRoot(),
+		Installed: res.Installed(),
+	}
+	if res.Version != nil {
+		status.Version = res.Version.String()
+		status.GoRoot = res.Version.LocalDir()
+	}
+
+	goExe := shim.Executable("go")
+	pathGo, err := shell.LookPath(goExe, os.Getenv("PATH"))
+	if err != nil {
+		return status, nil
+	}
+	status.PathGo = pathGo
+	if samePath(filepath.Dir(pathGo), consts.SHIMS_DIR) {
+		status.ViaShim = true
+		status.PathMatches = status.Installed
+		return status, nil
+	}
+	status.PathMatches = status.Installed && samePath(pathGo, filepath.Join(status.GoRoot, "bin", goExe))
+	return status, nil
+}
+
+// samePath 解析软链接后比较两个路径，GO_ROOT 软链接与其指向的版本目录视为相同
+func samePath(a, b string) bool {
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return ra == rb
+}
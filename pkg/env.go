@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/profile"
+	"github.com/the-yex/gvm/internal/shell"
+)
+
+// Environ 返回在 goRoot 对应版本下运行命令所需的环境变量：
+// GOROOT 指向该版本，PATH 中该版本的 bin 排在最前并移除其它 gvm 版本，
+// GOTOOLCHAIN=local 阻止 go 命令按 go.mod 自动切换到其它工具链。
+// 该版本使用 pkgset 时同时设置 GOPATH 与 GOBIN，并把 GOBIN 加入 PATH；
+// 最后应用配置 envs 中该版本的环境变量。
+func Environ(goRoot string, environ []string) ([]string, error) {
+	profileEnv, err := ProfileEnv(goRoot)
+	if err != nil {
+		return nil, err
+	}
+	gopath := PkgsetPath(goRoot)
+	entries := shell.RewritePath(shell.Getenv(environ, "PATH"), goRoot,
+		shell.Getenv(environ, shell.ActiveRootEnv), consts.GO_ROOT, shell.Getenv(environ, shell.ActiveGopathEnv), gopath)
+	environ = shell.WithEnv(environ, "GOROOT", goRoot)
+	environ = shell.WithEnv(environ, "PATH", strings.Join(shell.WithGopath(entries, gopath), string(os.PathListSeparator)))
+	if gopath != "" {
+		environ = shell.WithEnv(environ, "GOPATH", gopath)
+		environ = shell.WithEnv(environ, "GOBIN", filepath.Join(gopath, "bin"))
+	}
+	environ = shell.WithEnv(environ, "GOTOOLCHAIN", "local")
+	for _, kv := range profileEnv {
+		key, value, _ := strings.Cut(kv, "=")
+		environ = shell.WithEnv(environ, key, value)
+	}
+	return environ, nil
+}
+
+// ProfileEnv 返回配置 envs 中适用于 goRoot 对应版本的环境变量，全局版本按 GO_ROOT 软链接的目标解析
+func ProfileEnv(goRoot string) ([]string, error) {
+	dir := goRoot
+	if goRoot == consts.GO_ROOT {
+		if dir = (local{}).currentUsedVersionDir(); dir == "" {
+			return nil, nil
+		}
+	}
+	return profile.For(LocalInstalled(strings.TrimPrefix(filepath.Base(dir), "go")))
+}
@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// Exec 在版本 v 的环境下运行命令，不修改全局版本。
+// 收到的中断信号会转发给子进程，返回子进程的退出码；被信号终止时返回 128+信号值。
+func Exec(v *version.Version, name string, args []string) (int, error) {
+	goRoot := v.LocalDir()
+	env, err := Environ(goRoot, os.Environ())
+	if err != nil {
+		return 1, err
+	}
+	// 在新的 PATH 中查找命令，使 go 解析为该版本的 bin/go
+	path, err := shell.LookPath(name, shell.Getenv(env, "PATH"))
+	if err != nil {
+		return 127, err
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err = cmd.Start(); err != nil {
+		return 127, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return shell.ExitCode(cmd.Wait())
+}
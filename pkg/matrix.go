@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// MatrixVersions 将 --versions 中的每一项解析为已安装的版本，结果去重并按版本升序排列。
+// 每一项可以是具体版本（1.21.5）、部分版本（1.21，取最新补丁）、latest（最新正式版）
+// 或版本约束（>=1.21, ~1.22），约束会匹配所有已安装的版本。specs 为空时返回所有已安装的版本。
+func MatrixVersions(specs []string) ([]*version.Version, error) {
+	installed, err := local{}.List(consts.All, ListOption{})
+	if err != nil {
+		return nil, err
+	}
+	if len(installed) == 0 {
+		return nil, fmt.Errorf("no Go versions installed")
+	}
+	sort.Sort(version.Collection(installed))
+	if len(specs) == 0 {
+		return installed, nil
+	}
+
+	var (
+		versions []*version.Version
+		seen     = make(map[string]bool)
+	)
+	add := func(v *version.Version) {
+		if !seen[v.String()] {
+			seen[v.String()] = true
+			versions = append(versions, v)
+		}
+	}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if spec == "latest" {
+			v := latestRelease(installed)
+			if v == nil {
+				return nil, fmt.Errorf("no stable Go version installed for %q", spec)
+			}
+			add(v)
+			continue
+		}
+		if v := LocalInstalled(spec); v != nil {
+			add(v)
+			continue
+		}
+		c, err := version.NewConstraint(spec)
+		if err != nil {
+			return nil, fmt.Errorf("version %q is not installed", spec)
+		}
+		matched := false
+		for _, v := range installed {
+			if c.Check(v) {
+				add(v)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no installed version matches %q", spec)
+		}
+	}
+	sort.Sort(version.Collection(versions))
+	return versions, nil
+}
+
+// latestRelease 返回升序列表中最新的正式版
+func latestRelease(sorted []*version.Version) *version.Version {
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].Prerelease() == "" {
+			return sorted[i]
+		}
+	}
+	return nil
+}
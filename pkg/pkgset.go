@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/pkgset"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// PkgsetPath 返回在 goRoot 对应版本下使用的 GOPATH，该版本没有使用 pkgset 时返回空字符串。
+// 全局版本（GO_ROOT 软链接）使用 GOPATH_LINK，gvm use 切换版本后无需重新设置环境变量。
+func PkgsetPath(goRoot string) string {
+	if goRoot == consts.GO_ROOT {
+		if _, err := os.Lstat(consts.GOPATH_LINK); err == nil {
+			return consts.GOPATH_LINK
+		}
+		return ""
+	}
+	p, ok := pkgset.Active(filepath.Base(goRoot))
+	if !ok || pkgset.Ensure(p) != nil {
+		return ""
+	}
+	return p.Dir()
+}
+
+// PkgsetVersion 返回 pkgset 命令操作的版本：versionName 为空时使用当前目录生效的版本
+func PkgsetVersion(versionName string) (*version.Version, error) {
+	if versionName != "" {
+		if v := LocalInstalled(versionName); v != nil {
+			return v, nil
+		}
+		return nil, fmt.Errorf("version %q is not installed", versionName)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	res, err := Resolve(wd)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Installed() {
+		if res.Source == SourceGlobal {
+			return nil, fmt.Errorf("no Go version selected, run \"gvm use <version>\" first or pass --version")
+		}
+		return nil, res.NotInstalledError()
+	}
+	return res.Version, nil
+}
+
+// RelinkPkgset 在 pkgset 变化后更新全局版本的 GOPATH_LINK
+func RelinkPkgset() error {
+	versionDir := local{}.currentUsedVersionDir()
+	if versionDir == "" {
+		return nil
+	}
+	return pkgset.Link(filepath.Base(versionDir))
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/the-yex/gvm/internal/consts"
+	"github.com/the-yex/gvm/internal/project"
+	"github.com/the-yex/gvm/internal/version"
+)
+
+// SourceGlobal 表示使用 gvm use 设置的全局版本
+const SourceGlobal = "global"
+
+// Resolution 描述某个目录下应当使用的 Go 版本及其来源
+type Resolution struct {
+	Request string           // 请求的版本，全局版本为空
+	Source  string           // 决定版本的文件，或 SourceGlobal
+	Version *version.Version // 已安装的版本，项目锁定的版本未安装时为 nil
+}
+
+// GoRoot 返回该版本的 GOROOT，全局版本使用 GO_ROOT 软链接
+func (r Resolution) GoRoot() string {
+	if r.Source == SourceGlobal || r.Version == nil {
+		return consts.GO_ROOT
+	}
+	return r.Version.LocalDir()
+}
+
+// Installed 报告请求的版本是否已安装
+func (r Resolution) Installed() bool {
+	return r.Version != nil
+}
+
+// NotInstalledError 返回项目锁定的版本未安装时的提示
+func (r Resolution) NotInstalledError() error {
+	if r.Source == consts.ENV_GO_VERSION {
+		return fmt.Errorf("go%s required by %s is not installed, run \"gvm install %s\"", r.Request, r.Source, r.Request)
+	}
+	return fmt.Errorf("go%s required by %s is not installed, run \"gvm install\" in that directory", r.Request, r.Source)
+}
+
+// Resolve 解析 dir 下应当使用的版本，优先级为：
+// GVM_GO_VERSION 环境变量、项目版本文件、gvm use 设置的全局版本
+func Resolve(dir string) (Resolution, error) {
+	if request := os.Getenv(consts.ENV_GO_VERSION); request != "" {
+		return Resolution{
+			Request: request,
+			Source:  consts.ENV_GO_VERSION,
+			Version: LocalInstalled(request),
+		}, nil
+	}
+	pin, err := project.Find(dir)
+	if errors.Is(err, project.ErrNotFound) {
+		return Resolution{
+			Source:  SourceGlobal,
+			Version: LocalInstalled(local{}.currentUsedVersion()),
+		}, nil
+	}
+	if err != nil {
+		return Resolution{}, err
+	}
+	return Resolution{
+		Request: pin.Version,
+		Source:  pin.File,
+		Version: LocalInstalled(pin.Version),
+	}, nil
+}
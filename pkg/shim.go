@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/the-yex/gvm/internal/prettyout"
+	"github.com/the-yex/gvm/internal/shell"
+	"github.com/the-yex/gvm/internal/shim"
+)
+
+// ExecShim 以 shim 身份运行 name（go 或 gofmt）：按当前目录解析版本后执行该版本 bin 目录下的同名命令，成功时不会返回
+func ExecShim(name string, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	res, err := Resolve(wd)
+	if err != nil {
+		// 与 gvm hook 一致，版本文件无法解析时提示后使用全局版本，不影响 go 命令的使用
+		prettyout.PrettyWarm(os.Stderr, "gvm: %s\n", err.Error())
+		res = Resolution{Source: SourceGlobal}
+	}
+	if res.Source != SourceGlobal && !res.Installed() {
+		return res.NotInstalledError()
+	}
+	goRoot := res.GoRoot()
+	bin := filepath.Join(goRoot, "bin", shim.Executable(name))
+	if _, err = os.Stat(bin); err != nil {
+		if res.Source == SourceGlobal {
+			return fmt.Errorf("no Go version selected, run \"gvm use <version>\" first")
+		}
+		return err
+	}
+	// 显式设置 GOROOT，避免继承的 GOROOT 指向其它版本；使用 pkgset 时 go install 安装到 pkgset 中
+	env := shell.WithEnv(os.Environ(), "GOROOT", goRoot)
+	if gopath := PkgsetPath(goRoot); gopath != "" {
+		env = shell.WithEnv(env, "GOPATH", gopath)
+		env = shell.WithEnv(env, "GOBIN", filepath.Join(gopath, "bin"))
+	}
+	profileEnv, err := ProfileEnv(goRoot)
+	if err != nil {
+		return err
+	}
+	for _, kv := range profileEnv {
+		key, value, _ := strings.Cut(kv, "=")
+		env = shell.WithEnv(env, key, value)
+	}
+	return shim.Exec(bin, append([]string{bin}, args...), env)
+}
@@ -14,10 +14,13 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/viper"
+	"github.com/the-yex/gvm/internal/alias"
 	"github.com/the-yex/gvm/internal/consts"
 	"github.com/the-yex/gvm/internal/core"
+	"github.com/the-yex/gvm/internal/history"
+	"github.com/the-yex/gvm/internal/lock"
+	"github.com/the-yex/gvm/internal/pkgset"
 	"github.com/the-yex/gvm/internal/registry"
-	"github.com/the-yex/gvm/internal/utils"
 	"github.com/the-yex/gvm/internal/version"
 	"io"
 	"os"
@@ -40,6 +43,8 @@ type ListOption struct {
 	Timeout time.Duration
 	Mirror  string
 	Refresh bool
+	// Quiet 为 true 时获取远程版本不显示加载动画，用于结构化输出与非终端环境
+	Quiet bool
 }
 
 type VManager interface {
@@ -59,6 +64,7 @@ func init() {
 	core.MultiWriterInstall = remote{}.MultiWriterInstall
 	core.UninstallVersion = local{}.UninstallDir
 	core.InstallVersion = remote{}.Install
+	core.UseVersion = useVersion
 }
 func WithLocal() func(option *ManagerOption) {
 	return func(option *ManagerOption) {
@@ -95,7 +101,7 @@ func (l local) List(kind consts.VersionKind, opts ListOption) ([]*version.Versio
 			continue
 		}
 		for _, versionDir := range versionDirs {
-			if !versionDir.IsDir() {
+			if !versionDir.IsDir() || strings.HasPrefix(versionDir.Name(), ".") {
 				continue
 			}
 			v, err := version.NewVersion(strings.TrimPrefix(versionDir.Name(), "go"))
@@ -125,6 +131,9 @@ func (l local) currentUsedVersionDir() string {
 }
 
 func (l local) Uninstall(versionName string) error {
+	return lock.Do(func() error { return l.uninstall(versionName) })
+}
+func (l local) uninstall(versionName string) error {
 	if versionName == l.currentUsedVersion() {
 		return fmt.Errorf("cannot uninstall version %s: it is currently in use\n", versionName)
 	}
@@ -137,11 +146,15 @@ func (l local) Uninstall(versionName string) error {
 		if err := os.RemoveAll(targetDir); err != nil {
 			return fmt.Errorf("uninstall failed: %s\n", err.Error())
 		}
+		recordHistory(history.Uninstall, versionName)
 		return nil
 	}
 	return fmt.Errorf("version %q is not installed\n", versionName)
 }
 func (l local) UninstallDir(versionDir string) error {
+	return lock.Do(func() error { return l.uninstallDir(versionDir) })
+}
+func (l local) uninstallDir(versionDir string) error {
 	if versionDir == l.currentUsedVersion() {
 		return fmt.Errorf("cannot uninstall version %s: it is currently in use\n", versionDir)
 	}
@@ -151,6 +164,7 @@ func (l local) UninstallDir(versionDir string) error {
 	if err := os.RemoveAll(versionDir); err != nil {
 		return fmt.Errorf("uninstall failed: %s\n", err.Error())
 	}
+	recordHistory(history.Uninstall, strings.TrimPrefix(filepath.Base(versionDir), "go"))
 	return nil
 }
 
@@ -176,50 +190,52 @@ func (r remote) mergeInstalled(remoteVers []*version.Version, localVers []*versi
 func (r remote) List(kind consts.VersionKind, opts ListOption) (versions []*version.Version, err error) {
 	mirrorURL := resolveMirrorURL(opts)
 	cacheHit := false
+	var kinds registry.Kinds
 	setRemoteCacheInfo(RemoteCacheInfo{Mirror: mirrorURL, Forced: opts.Refresh})
 	if !opts.Refresh {
-		if cached, createdAt, ok, cacheErr := loadRemoteCache(mirrorURL); ok && cacheErr == nil {
-			versions = cached
+		if cached, cachedKinds, servedBy, createdAt, ok, cacheErr := loadRemoteCache(mirrorURL); ok && cacheErr == nil {
+			versions, kinds = cached, cachedKinds
 			cacheHit = true
-			setRemoteCacheInfo(RemoteCacheInfo{Mirror: mirrorURL, Used: true, Created: createdAt})
+			setRemoteCacheInfo(RemoteCacheInfo{Mirror: mirrorURL, ServedBy: servedBy, Used: true, Created: createdAt})
 		}
 	}
 
 	if !cacheHit {
-		p := core.NewSpinnerProgram(tea.WithAltScreen())
-		wg := sync.WaitGroup{}
-		wg.Go(func() {
-			p.Run()
-		})
+		stopSpinner := func() {}
+		if !opts.Quiet {
+			p := core.NewSpinnerProgram(tea.WithAltScreen())
+			wg := sync.WaitGroup{}
+			wg.Go(func() {
+				p.Run()
+			})
+			stopSpinner = func() {
+				p.Send(tea.Quit())
+				wg.Wait()
+			}
+		}
 		regOpts := registry.RegistryOption{Timeout: opts.Timeout, Mirror: mirrorURL}
 		if regOpts.Timeout == 0 {
 			regOpts.Timeout = 5 * time.Second
 		}
-		rg, err := registry.NewRegistry(regOpts)
+		rg, served, err := registry.Resolve(regOpts)
 		if err != nil {
-			p.Send(tea.Quit())
-			wg.Wait()
+			stopSpinner()
 			return nil, err
 		}
-		switch kind {
-		case consts.Stable:
-			versions, err = rg.StableVersions()
-		case consts.Unstable:
-			versions, err = rg.UnstableVersions()
-		case consts.Archived:
-			versions, err = rg.ArchivedVersions()
-		default:
-			versions, err = rg.AllVersions()
+		// 缓存完整的版本列表及各版本的类型，按类型筛选在读取后进行，避免某一类型的结果被当作所有版本缓存
+		versions, kinds, err = registry.Classify(rg, served.Parser)
+		// 缓存以主镜像为键，备用镜像提供的数据不缓存，避免主镜像恢复后仍读到备用镜像的版本列表
+		if primary, lookupErr := registry.LookupMirror(mirrorURL); err == nil && lookupErr == nil && served.URL == primary.URL {
+			saveRemoteCache(mirrorURL, served.String(), versions, kinds)
 		}
-		saveRemoteCache(mirrorURL, versions)
-		setRemoteCacheInfo(RemoteCacheInfo{Mirror: mirrorURL, Used: false, Created: time.Now(), Forced: opts.Refresh})
-		p.Send(tea.Quit())
-		wg.Wait()
+		setRemoteCacheInfo(RemoteCacheInfo{Mirror: mirrorURL, ServedBy: served.String(), Used: false, Created: time.Now(), Forced: opts.Refresh})
+		stopSpinner()
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	versions = registry.FilterKind(versions, kinds, kind)
 	if r.withLocal {
 		installVersions, _ := local{}.List(kind, opts)
 		r.mergeInstalled(versions, installVersions)
@@ -228,28 +244,70 @@ func (r remote) List(kind consts.VersionKind, opts ListOption) (versions []*vers
 }
 
 func (r remote) Install(versionName string) error {
-	versions, err := (&remote{withLocal: false}).List(consts.All, ListOption{})
+	return lock.Do(func() error { return r.install(versionName, true) })
+}
+
+// InstallLatestMatch 安装项目锁定的版本：1.21 这样的部分版本直接选择最新的补丁版本，不弹出选择界面
+func InstallLatestMatch(versionName string) error {
+	return lock.Do(func() error { return remote{}.install(versionName, false) })
+}
+
+// InstallOnly 与 InstallLatestMatch 相同地选择版本并安装，但不切换全局版本，返回安装后的本地版本
+func InstallOnly(versionName string) (v *version.Version, err error) {
+	err = lock.Do(func() error {
+		v, err = remote{}.download(versionName, false)
+		return err
+	})
+	return v, err
+}
+
+func (r remote) install(versionName string, interactive bool) error {
+	v, err := r.download(versionName, interactive)
 	if err != nil {
 		return err
 	}
-	v, err := version.NewFinder(versions).Find(versionName)
-	if err != nil {
+	fmt.Println(v.LocalDir())
+	if err = switchVersionLocked(v.LocalDir()); err != nil {
 		return err
 	}
+	printNowUsing()
+	return nil
+}
+
+// download 从镜像查找并安装版本，不修改 GO_ROOT
+func (r remote) download(versionName string, interactive bool) (*version.Version, error) {
+	versionName = alias.Resolve(versionName)
+	versions, err := (&remote{withLocal: false}).List(consts.All, ListOption{})
+	if err != nil {
+		return nil, err
+	}
+	finder := version.NewFinder(versions)
+	var v *version.Version
+	if interactive {
+		v, err = finder.Find(versionName)
+	} else {
+		v, err = finder.FindLatestMatch(versionName)
+	}
+	if err != nil {
+		return nil, err
+	}
 	if LocalInstalled(v.String()) != nil {
-		return fmt.Errorf("%s has already been installed\n", v.String())
+		return nil, fmt.Errorf("%s has already been installed\n", v.String())
 	}
-	err = v.Install()
-	if nil != err {
-		return err
+	if err = v.Install(); err != nil {
+		return nil, err
 	}
+	recordHistory(history.Install, v.String())
 	v.Path = consts.VERSION_DIR
 	v.DirName = fmt.Sprintf("go%s", v.String())
-	fmt.Println(v.LocalDir())
-	return SwitchVersion(v.LocalDir())
+	return v, nil
 }
 
 func (r remote) MultiWriterInstall(item any, writer io.Writer, fn func(int642 int64)) error {
+	return lock.Do(func() error { return r.multiWriterInstall(item, writer, fn) })
+}
+
+func (r remote) multiWriterInstall(item any, writer io.Writer, fn func(int642 int64)) error {
 	v, ok := item.(*version.Version)
 	if !ok {
 		return errors.New("invalid version")
@@ -265,6 +323,7 @@ func (r remote) MultiWriterInstall(item any, writer io.Writer, fn func(int642 in
 	if nil != err {
 		return err
 	}
+	recordHistory(history.Install, v.String())
 	return nil
 }
 
@@ -277,24 +336,54 @@ func (r remote) Uninstall(version string) error {
 软连接go指定版本的本地目录
 */
 func SwitchVersion(versionDir string) error {
-	os.Remove(consts.GO_ROOT)
-	_, err := os.Stat(versionDir)
-	if err != nil {
+	if err := useVersion(versionDir); err != nil {
 		return err
 	}
-	if err = utils.Symlink(versionDir, consts.GO_ROOT); err != nil {
+	printNowUsing()
+	return nil
+}
+
+// useVersion 持有全局锁切换版本，不输出信息，供交互界面使用
+func useVersion(versionDir string) error {
+	return lock.Do(func() error { return switchVersionLocked(versionDir) })
+}
+
+// switchVersionLocked 在已持有全局锁时切换版本：替换 GO_ROOT、更新 pkgset 软链接并记录历史，
+// 所有切换版本的操作都经过这里。安装后切换使用它避免重复加锁
+func switchVersionLocked(versionDir string) error {
+	if err := core.LinkGoRoot(versionDir); err != nil {
 		return err
 	}
+	if err := pkgset.Link(filepath.Base(versionDir)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: pkgset: %s\n", err.Error())
+	}
+	recordHistory(history.Use, strings.TrimPrefix(filepath.Base(versionDir), "go"))
+	return nil
+}
+
+func printNowUsing() {
 	if output, err := exec.Command(filepath.Join(consts.GO_ROOT, "bin", "go"), "version").Output(); err == nil {
 		fmt.Printf("Now using %s", strings.TrimPrefix(string(output), "go version "))
 	}
-	return nil
+}
+
+// recordHistory 记录切换与安装历史，写入失败不影响操作本身
+func recordHistory(action history.Action, versionName string) {
+	if err := history.Record(action, versionName); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: history: %s\n", err.Error())
+	}
+}
+
+// PreviousVersion 返回切换到当前全局版本之前使用的版本
+func PreviousVersion() (string, error) {
+	return history.Previous(local{}.currentUsedVersion())
 }
 
 func LocalInstalled(versionName string) *version.Version {
 	if versionName == "" {
 		return nil
 	}
+	versionName = alias.Resolve(versionName)
 	installVersions, _ := local{}.List(consts.All, ListOption{})
 	cleaned := strings.TrimSpace(strings.TrimPrefix(versionName, "go"))
 
@@ -304,18 +393,19 @@ func LocalInstalled(versionName string) *version.Version {
 		}
 	}
 
-	target, err := version.NewVersion(cleaned)
+	target, err := version.NewGoVersion(cleaned)
 	if err != nil {
 		return nil
 	}
 
-	for _, installVersion := range installVersions {
-		if installVersion.Equal(target) {
-			return installVersion
+	// 1.21 这样的部分版本取已安装的最新补丁版本，而不是恰好等于 1.21.0 的版本
+	if hasPatchComponent(cleaned) || target.Prerelease() != "" {
+		for _, installVersion := range installVersions {
+			if installVersion.Equal(target) {
+				return installVersion
+			}
 		}
-	}
-
-	if !hasPatchComponent(cleaned) {
+	} else {
 		var matches []*version.Version
 		for _, installVersion := range installVersions {
 			if installVersion.Major() == target.Major() && installVersion.Minor() == target.Minor() {
@@ -346,53 +436,58 @@ func resolveMirrorURL(opts ListOption) string {
 
 type remoteCacheVersion struct {
 	Original  string                 `json:"original"`
+	Kind      consts.VersionKind     `json:"kind"`
 	Artifacts []version.ArtifactInfo `json:"artifacts"`
 }
 
 type remoteCacheFile struct {
 	CreatedAt time.Time            `json:"created_at"`
 	Mirror    string               `json:"mirror"`
+	ServedBy  string               `json:"served_by,omitempty"`
 	Versions  []remoteCacheVersion `json:"versions"`
 }
 
 func cacheFilePath(mirror string) string {
 	sum := sha256.Sum256([]byte(mirror))
-	filename := fmt.Sprintf("versions_%x.json", sum[:8])
+	// v3: 缓存完整的版本列表及各版本的类型；旧版本的缓存可能只包含某一类型的版本，或没有类型
+	filename := fmt.Sprintf("versions_v3_%x.json", sum[:8])
 	return filepath.Join(consts.CACHE_DIR, filename)
 }
 
-func loadRemoteCache(mirror string) ([]*version.Version, time.Time, bool, error) {
+func loadRemoteCache(mirror string) ([]*version.Version, registry.Kinds, string, time.Time, bool, error) {
 	path := cacheFilePath(mirror)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, time.Time{}, false, nil
+			return nil, nil, "", time.Time{}, false, nil
 		}
-		return nil, time.Time{}, false, err
+		return nil, nil, "", time.Time{}, false, err
 	}
 	var cache remoteCacheFile
 	if err := json.Unmarshal(data, &cache); err != nil {
-		return nil, time.Time{}, false, err
+		return nil, nil, "", time.Time{}, false, err
 	}
 	if time.Since(cache.CreatedAt) > remoteCacheTTL {
-		return nil, time.Time{}, false, nil
+		return nil, nil, "", time.Time{}, false, nil
 	}
 	var versions []*version.Version
+	kinds := make(registry.Kinds, len(cache.Versions))
 	for _, entry := range cache.Versions {
 		v, err := version.NewGoVersion(entry.Original)
 		if err != nil {
 			continue
 		}
 		v.Artifacts = entry.Artifacts
+		kinds[v.Original()] = entry.Kind
 		versions = append(versions, v)
 	}
 	if len(versions) == 0 {
-		return nil, time.Time{}, false, nil
+		return nil, nil, "", time.Time{}, false, nil
 	}
-	return versions, cache.CreatedAt, true, nil
+	return versions, kinds, cache.ServedBy, cache.CreatedAt, true, nil
 }
 
-func saveRemoteCache(mirror string, versions []*version.Version) {
+func saveRemoteCache(mirror, servedBy string, versions []*version.Version, kinds registry.Kinds) {
 	if len(versions) == 0 {
 		return
 	}
@@ -400,12 +495,14 @@ func saveRemoteCache(mirror string, versions []*version.Version) {
 	for _, v := range versions {
 		entries = append(entries, remoteCacheVersion{
 			Original:  v.Original(),
+			Kind:      kinds[v.Original()],
 			Artifacts: v.Artifacts,
 		})
 	}
 	cache := remoteCacheFile{
 		CreatedAt: time.Now(),
 		Mirror:    mirror,
+		ServedBy:  servedBy,
 		Versions:  entries,
 	}
 	data, err := json.Marshal(cache)
@@ -427,3 +524,15 @@ func hasPatchComponent(ver string) bool {
 	trimmed = strings.SplitN(trimmed, "+", 2)[0]
 	return len(strings.Split(trimmed, ".")) >= 3
 }
+
+// AliasesOf 返回指向已安装版本 v 的别名
+func AliasesOf(v *version.Version) []string {
+	aliases, _ := alias.List()
+	var names []string
+	for _, a := range aliases {
+		if target := LocalInstalled(a.Version); target != nil && target.Equal(v) {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}